@@ -0,0 +1,5 @@
+
+-- +migrate Up
+ALTER TABLE `stories` ADD FULLTEXT INDEX `ft_stories_title_content` (`title`, `content`);
+-- +migrate Down
+ALTER TABLE `stories` DROP INDEX `ft_stories_title_content`;
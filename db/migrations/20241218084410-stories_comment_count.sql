@@ -0,0 +1,9 @@
+
+-- +migrate Up
+ALTER TABLE `stories` ADD COLUMN `comment_count` int(11) NOT NULL DEFAULT 0 AFTER `user_id`;
+CREATE INDEX `idx_stories_user_id` ON `stories` (`user_id`);
+CREATE INDEX `idx_stories_created_at` ON `stories` (`created_at`);
+-- +migrate Down
+DROP INDEX `idx_stories_created_at` ON `stories`;
+DROP INDEX `idx_stories_user_id` ON `stories`;
+ALTER TABLE `stories` DROP COLUMN `comment_count`;
@@ -0,0 +1,5 @@
+
+-- +migrate Up
+ALTER TABLE `stories` ADD COLUMN `version` int(11) NOT NULL DEFAULT 1 AFTER `comment_count`;
+-- +migrate Down
+ALTER TABLE `stories` DROP COLUMN `version`;
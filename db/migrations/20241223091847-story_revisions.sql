@@ -0,0 +1,17 @@
+
+-- +migrate Up
+CREATE TABLE `story_revisions` (
+    `id` int(11) NOT NULL AUTO_INCREMENT,
+    `story_id` int(11) NOT NULL,
+    `revision` int(11) NOT NULL,
+    `title` varchar(255) NOT NULL,
+    `content` text NOT NULL,
+    `thumbnail_url` varchar(255) NOT NULL,
+    `category_id` int(11) NOT NULL,
+    `created_at` timestamp NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `story_revisions_story_id_revision` (`story_id`, `revision`),
+    FOREIGN KEY (`story_id`) REFERENCES stories (`id`)
+);
+-- +migrate Down
+DROP TABLE IF EXISTS `story_revisions`;
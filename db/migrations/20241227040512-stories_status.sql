@@ -0,0 +1,13 @@
+
+-- +migrate Up
+ALTER TABLE `stories`
+    ADD COLUMN `status` varchar(16) NOT NULL DEFAULT 'draft' AFTER `user_id`,
+    ADD COLUMN `published_at` timestamp NULL DEFAULT NULL AFTER `status`,
+    ADD INDEX `stories_status_published_at` (`status`, `published_at`);
+-- Stories created before the lifecycle existed were public already
+UPDATE `stories` SET `status` = 'published', `published_at` = `created_at`, `updated_at` = `updated_at`;
+-- +migrate Down
+ALTER TABLE `stories`
+    DROP INDEX `stories_status_published_at`,
+    DROP COLUMN `published_at`,
+    DROP COLUMN `status`;
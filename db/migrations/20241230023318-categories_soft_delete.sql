@@ -0,0 +1,5 @@
+
+-- +migrate Up
+ALTER TABLE `categories` ADD COLUMN `deleted_at` timestamp NULL DEFAULT NULL AFTER `updated_at`;
+-- +migrate Down
+ALTER TABLE `categories` DROP COLUMN `deleted_at`;
@@ -0,0 +1,23 @@
+
+-- +migrate Up
+-- Existing rows get ID based slugs, titles changed from now on get readable ones
+ALTER TABLE `stories` ADD COLUMN `slug` varchar(255) NULL AFTER `title`;
+UPDATE `stories` SET `slug` = CONCAT('story-', `id`), `updated_at` = `updated_at`;
+ALTER TABLE `stories` MODIFY `slug` varchar(255) NOT NULL, ADD UNIQUE INDEX `stories_slug` (`slug`);
+
+ALTER TABLE `categories` ADD COLUMN `slug` varchar(255) NULL AFTER `name`;
+UPDATE `categories` SET `slug` = CONCAT('category-', `id`), `updated_at` = `updated_at`;
+ALTER TABLE `categories` MODIFY `slug` varchar(255) NOT NULL, ADD UNIQUE INDEX `categories_slug` (`slug`);
+
+CREATE TABLE `slug_redirects` (
+    `resource` varchar(16) NOT NULL,
+    `slug` varchar(255) NOT NULL,
+    `target_id` int(11) NOT NULL,
+    `created_at` timestamp NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (`resource`, `slug`),
+    INDEX `slug_redirects_target` (`resource`, `target_id`)
+);
+-- +migrate Down
+DROP TABLE IF EXISTS `slug_redirects`;
+ALTER TABLE `categories` DROP INDEX `categories_slug`, DROP COLUMN `slug`;
+ALTER TABLE `stories` DROP INDEX `stories_slug`, DROP COLUMN `slug`;
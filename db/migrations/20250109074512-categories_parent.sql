@@ -0,0 +1,10 @@
+
+-- +migrate Up
+ALTER TABLE `categories`
+    ADD COLUMN `parent_id` int(11) NULL DEFAULT NULL AFTER `slug`,
+    ADD CONSTRAINT `categories_parent_id` FOREIGN KEY (`parent_id`) REFERENCES categories (`id`);
+-- +migrate Down
+ALTER TABLE `categories`
+    DROP FOREIGN KEY `categories_parent_id`,
+    DROP INDEX `categories_parent_id`,
+    DROP COLUMN `parent_id`;
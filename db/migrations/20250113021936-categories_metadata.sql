@@ -0,0 +1,25 @@
+
+-- +migrate Up
+ALTER TABLE `categories`
+    ADD COLUMN `description` varchar(1000) NOT NULL DEFAULT '' AFTER `slug`,
+    ADD COLUMN `icon_url` varchar(255) NOT NULL DEFAULT '' AFTER `description`,
+    ADD COLUMN `color` varchar(7) NOT NULL DEFAULT '' AFTER `icon_url`,
+    ADD COLUMN `position` int(11) NOT NULL DEFAULT 0 AFTER `color`;
+-- Rename existing duplicates so the unique index can be created
+UPDATE `categories` AS c
+    JOIN `categories` AS d ON LOWER(d.`name`) = LOWER(c.`name`) AND d.`id` < c.`id` AND d.`deleted_at` IS NULL
+    SET c.`name` = CONCAT(c.`name`, ' (', c.`id`, ')'), c.`updated_at` = c.`updated_at`
+    WHERE c.`deleted_at` IS NULL;
+UPDATE `categories` SET `position` = `id`, `updated_at` = `updated_at`;
+-- Names are unique among categories that are not deleted, ignoring case
+ALTER TABLE `categories`
+    ADD COLUMN `name_key` varchar(255) AS (IF(`deleted_at` IS NULL, LOWER(`name`), NULL)) STORED,
+    ADD UNIQUE INDEX `categories_name_key` (`name_key`);
+-- +migrate Down
+ALTER TABLE `categories`
+    DROP INDEX `categories_name_key`,
+    DROP COLUMN `name_key`,
+    DROP COLUMN `position`,
+    DROP COLUMN `color`,
+    DROP COLUMN `icon_url`,
+    DROP COLUMN `description`;
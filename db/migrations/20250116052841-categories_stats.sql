@@ -0,0 +1,17 @@
+
+-- +migrate Up
+ALTER TABLE `categories`
+    ADD COLUMN `story_count` int(11) NOT NULL DEFAULT 0 AFTER `position`,
+    ADD COLUMN `latest_story_at` timestamp NULL DEFAULT NULL AFTER `story_count`;
+UPDATE `categories` AS c
+    LEFT JOIN (
+        SELECT `category_id`, COUNT(*) AS `story_count`, MAX(`published_at`) AS `latest_story_at`
+        FROM `stories`
+        WHERE `status` = 'published' AND `deleted_at` IS NULL
+        GROUP BY `category_id`
+    ) AS s ON s.`category_id` = c.`id`
+    SET c.`story_count` = COALESCE(s.`story_count`, 0), c.`latest_story_at` = s.`latest_story_at`, c.`updated_at` = c.`updated_at`;
+-- +migrate Down
+ALTER TABLE `categories`
+    DROP COLUMN `latest_story_at`,
+    DROP COLUMN `story_count`;
@@ -0,0 +1,21 @@
+
+-- +migrate Up
+CREATE TABLE `tags` (
+    `id` int(11) NOT NULL AUTO_INCREMENT,
+    `name` varchar(50) NOT NULL,
+    `slug` varchar(64) NOT NULL,
+    `created_at` timestamp NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `tags_slug` (`slug`)
+);
+CREATE TABLE `story_tags` (
+    `story_id` int(11) NOT NULL,
+    `tag_id` int(11) NOT NULL,
+    PRIMARY KEY (`story_id`, `tag_id`),
+    INDEX `story_tags_tag_id` (`tag_id`),
+    FOREIGN KEY (`story_id`) REFERENCES stories (`id`),
+    FOREIGN KEY (`tag_id`) REFERENCES tags (`id`)
+);
+-- +migrate Down
+DROP TABLE IF EXISTS `story_tags`;
+DROP TABLE IF EXISTS `tags`;
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+)
+
+// Claims are the access token claims this service relies on. The user ID is
+// read from user_id, falling back to the standard sub claim.
+type Claims struct {
+	UserID int64    `json:"user_id"`
+	Roles  []string `json:"roles"`
+	jwt.RegisteredClaims
+}
+
+// minSecretLength is the shortest HS256 secret accepted, matching the size of
+// the SHA-256 output.
+const minSecretLength = 32
+
+// placeholderSecrets are example secrets that must never sign real tokens.
+var placeholderSecrets = []string{"change-me", "changeme", "secret"}
+
+// VerifierOptions configures a Verifier. HS256 uses Secret as the shared key,
+// RS256 uses PublicKeyPEM. Issuer and Audience, when set, must match the
+// token's iss and aud claims.
+type VerifierOptions struct {
+	Algorithm    string
+	Secret       string
+	PublicKeyPEM string
+	Issuer       string
+	Audience     string
+}
+
+// Verifier validates access tokens signed with HS256 or RS256.
+type Verifier struct {
+	algorithm string
+	key       any
+	options   []jwt.ParserOption
+}
+
+func NewVerifier(opts VerifierOptions) (*Verifier, error) {
+	verifier := &Verifier{}
+	switch strings.ToUpper(opts.Algorithm) {
+	case "", jwt.SigningMethodHS256.Alg():
+		if err := checkSecret(opts.Secret); err != nil {
+			return nil, err
+		}
+		verifier.algorithm, verifier.key = jwt.SigningMethodHS256.Alg(), []byte(opts.Secret)
+	case jwt.SigningMethodRS256.Alg():
+		key, err := jwt.ParseRSAPublicKeyFromPEM([]byte(opts.PublicKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("parse jwt public key: %w", err)
+		}
+		verifier.algorithm, verifier.key = jwt.SigningMethodRS256.Alg(), key
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm %q", opts.Algorithm)
+	}
+
+	verifier.options = []jwt.ParserOption{
+		jwt.WithValidMethods([]string{verifier.algorithm}),
+		jwt.WithExpirationRequired(),
+	}
+	if opts.Issuer != "" {
+		verifier.options = append(verifier.options, jwt.WithIssuer(opts.Issuer))
+	}
+	if opts.Audience != "" {
+		verifier.options = append(verifier.options, jwt.WithAudience(opts.Audience))
+	}
+
+	return verifier, nil
+}
+
+// checkSecret rejects HS256 secrets that are missing, left at an example
+// value or too short to resist guessing.
+func checkSecret(secret string) error {
+	if secret == "" {
+		return errors.New("jwt secret is required for HS256")
+	}
+	for _, placeholder := range placeholderSecrets {
+		if strings.EqualFold(secret, placeholder) {
+			return errors.New("jwt secret is still set to an example value")
+		}
+	}
+	if len(secret) < minSecretLength {
+		return fmt.Errorf("jwt secret must be at least %d bytes", minSecretLength)
+	}
+
+	return nil
+}
+
+// Verify checks the token signature, expiry, issuer and audience and returns
+// its caller. Tokens without an expiry are rejected.
+func (v *Verifier) Verify(tokenString string) (*model.Caller, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(*jwt.Token) (any, error) {
+		return v.key, nil
+	}, v.options...)
+	if err != nil {
+		return nil, errors.Join(model.ErrUnauthenticated, err)
+	}
+
+	userID := claims.UserID
+	if userID == 0 {
+		userID, _ = strconv.ParseInt(claims.Subject, 10, 64)
+	}
+
+	if userID <= 0 {
+		return nil, errors.Join(model.ErrUnauthenticated, errors.New("token has no user id"))
+	}
+
+	return &model.Caller{
+		UserID: userID,
+		Roles:  claims.Roles,
+	}, nil
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer <token>"
+// header value.
+func BearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
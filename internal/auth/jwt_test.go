@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+)
+
+var testSecret = strings.Repeat("s", minSecretLength)
+
+func signToken(t *testing.T, claims Claims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestNewVerifierSecret(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		valid  bool
+	}{
+		{name: "empty", secret: "", valid: false},
+		{name: "placeholder", secret: "change-me", valid: false},
+		{name: "too short", secret: "short-secret", valid: false},
+		{name: "long enough", secret: testSecret, valid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewVerifier(VerifierOptions{Algorithm: "HS256", Secret: tt.secret})
+			if (err == nil) != tt.valid {
+				t.Errorf("expected valid %v, got error %v", tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	verifier, err := NewVerifier(VerifierOptions{Secret: testSecret, Issuer: "accounts", Audience: "stories"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := jwt.RegisteredClaims{
+		Issuer:    "accounts",
+		Audience:  jwt.ClaimStrings{"stories"},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+
+	tests := []struct {
+		name   string
+		claims func(claims *jwt.RegisteredClaims)
+		valid  bool
+	}{
+		{name: "valid", claims: func(*jwt.RegisteredClaims) {}, valid: true},
+		{name: "no expiry", claims: func(c *jwt.RegisteredClaims) { c.ExpiresAt = nil }, valid: false},
+		{name: "expired", claims: func(c *jwt.RegisteredClaims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour)) }, valid: false},
+		{name: "other issuer", claims: func(c *jwt.RegisteredClaims) { c.Issuer = "elsewhere" }, valid: false},
+		{name: "other audience", claims: func(c *jwt.RegisteredClaims) { c.Audience = jwt.ClaimStrings{"comments"} }, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registered := valid
+			tt.claims(&registered)
+
+			caller, err := verifier.Verify(signToken(t, Claims{UserID: 7, RegisteredClaims: registered}))
+			if !tt.valid {
+				if !errors.Is(err, model.ErrUnauthenticated) {
+					t.Fatalf("expected ErrUnauthenticated, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if caller.UserID != 7 {
+				t.Errorf("expected user 7, got %d", caller.UserID)
+			}
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package config
 
-import "github.com/spf13/viper"
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
 
 func ENV() string {
 	return viper.GetString("env")
@@ -27,4 +31,83 @@ func GetDbPassword() string {
 }
 func CommentgRPCHost()string{
 	return viper.GetString("comment_service.grpc_host")
-}
\ No newline at end of file
+}
+
+func CommentServiceMode() string {
+	return viper.GetString("comment_service.mode")
+}
+
+// CommentServiceTimeout is the deadline of each call to the comment service.
+func CommentServiceTimeout() time.Duration {
+	return viper.GetDuration("comment_service.timeout")
+}
+
+func CommentServiceMaxRetries() int {
+	return viper.GetInt("comment_service.max_retries")
+}
+
+func CommentServiceRetryBackoff() time.Duration {
+	return viper.GetDuration("comment_service.retry_backoff")
+}
+
+func CommentServiceBreakerThreshold() int {
+	return viper.GetInt("comment_service.breaker_threshold")
+}
+
+func CommentServiceBreakerCooldown() time.Duration {
+	return viper.GetDuration("comment_service.breaker_cooldown")
+}
+
+func GRPCPort() string {
+	return viper.GetString("grpc.port")
+}
+
+func AccountServiceMode() string {
+	return viper.GetString("account_service.mode")
+}
+
+func AccountServiceBaseURL() string {
+	return viper.GetString("account_service.base_url")
+}
+
+func AccountServiceTimeout() time.Duration {
+	return viper.GetDuration("account_service.timeout")
+}
+
+func JWTAlgorithm() string {
+	return viper.GetString("jwt.algorithm")
+}
+
+func JWTSecret() string {
+	return viper.GetString("jwt.secret")
+}
+
+func JWTPublicKey() string {
+	return viper.GetString("jwt.public_key")
+}
+
+func JWTIssuer() string {
+	return viper.GetString("jwt.issuer")
+}
+
+func JWTAudience() string {
+	return viper.GetString("jwt.audience")
+}
+
+// HTTPCacheControl returns the Cache-Control header of each cacheable HTTP
+// route, keyed by route name.
+func HTTPCacheControl() map[string]string {
+	return viper.GetStringMapString("http.cache_control")
+}
+
+func SchedulerPublishInterval() time.Duration {
+	return viper.GetDuration("scheduler.publish_interval")
+}
+
+func SchedulerCommentCountInterval() time.Duration {
+	return viper.GetDuration("scheduler.comment_count_interval")
+}
+
+func TrashRetention() time.Duration {
+	return viper.GetDuration("trash.retention")
+}
@@ -0,0 +1,46 @@
+package console
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/kodinggo/gb-2-api-story-service/db"
+	"github.com/kodinggo/gb-2-api-story-service/internal/config"
+	"github.com/kodinggo/gb-2-api-story-service/internal/repository"
+	"github.com/kodinggo/gb-2-api-story-service/internal/usecase"
+	"github.com/spf13/cobra"
+)
+
+var retention time.Duration
+
+func init() {
+	rootCmd.AddCommand(purgeTrashCMD)
+
+	purgeTrashCMD.Flags().DurationVarP(&retention, "retention", "r", 0, "Purge stories deleted longer ago than this, defaults to trash.retention")
+}
+
+var purgeTrashCMD = &cobra.Command{
+	Use:   "purge-trash",
+	Short: "Permanently delete stories soft deleted longer than the retention period",
+	Run:   purgeTrash,
+}
+
+func purgeTrash(cmd *cobra.Command, args []string) {
+	if retention <= 0 {
+		retention = config.TrashRetention()
+	}
+
+	mysql := db.NewMysql()
+	defer mysql.Close()
+
+	// Purging needs neither the comment nor the account service
+	storyUsecase := usecase.NewStoryUsecase(repository.NewStoryRepo(mysql), nil, repository.NewCategoryRepo(mysql), nil, repository.NewTagRepo(mysql), repository.NewTransactor(mysql))
+
+	n, err := storyUsecase.PurgeExpired(context.Background(), retention)
+	if err != nil {
+		log.Panicf("Error purging deleted stories, %s", err.Error())
+	}
+
+	log.Printf("Successfully purged %d stories", n)
+}
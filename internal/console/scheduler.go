@@ -0,0 +1,68 @@
+package console
+
+import (
+	"context"
+	"time"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultPublishInterval      = time.Minute
+	defaultCommentCountInterval = 5 * time.Minute
+)
+
+// runPublishScheduler publishes due scheduled stories every interval until ctx
+// is done.
+func runPublishScheduler(ctx context.Context, storyUsecase model.IStoryUsecase, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultPublishInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			published, err := storyUsecase.PublishScheduled(ctx)
+			if err != nil {
+				// Already logged by the usecase, try again on the next tick
+				continue
+			}
+			if published > 0 {
+				logrus.Infof("published %d scheduled stories", published)
+			}
+		}
+	}
+}
+
+// runCommentCountScheduler syncs the stored comment counts with the comment
+// service every interval until ctx is done.
+func runCommentCountScheduler(ctx context.Context, storyUsecase model.IStoryUsecase, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultCommentCountInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			updated, err := storyUsecase.SyncCommentCounts(ctx)
+			if err != nil {
+				// Already logged by the usecase, try again on the next tick
+				continue
+			}
+			if updated > 0 {
+				logrus.Infof("updated %d story comment counts", updated)
+			}
+		}
+	}
+}
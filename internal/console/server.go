@@ -1,16 +1,23 @@
 package console
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net"
 	"sync"
 
 	"github.com/kodinggo/gb-2-api-comment-service/pb/comment_service"
 	"github.com/kodinggo/gb-2-api-story-service/db"
+	"github.com/kodinggo/gb-2-api-story-service/internal/auth"
 	"github.com/kodinggo/gb-2-api-story-service/internal/config"
+	handlerGrpc "github.com/kodinggo/gb-2-api-story-service/internal/delivery/grpc"
 	handlerHttp "github.com/kodinggo/gb-2-api-story-service/internal/delivery/http"
+	"github.com/kodinggo/gb-2-api-story-service/internal/fake"
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
 	"github.com/kodinggo/gb-2-api-story-service/internal/repository"
 	"github.com/kodinggo/gb-2-api-story-service/internal/usecase"
+	pb "github.com/kodinggo/gb-2-api-story-service/pb/story_service"
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -34,14 +41,41 @@ func httpServer(cmd *cobra.Command, args []string) {
 
 	storyRepo := repository.NewStoryRepo(mysql)
 	categoryRepo := repository.NewCategoryRepo(mysql)
+	tagRepo := repository.NewTagRepo(mysql)
+	transactor := repository.NewTransactor(mysql)
 	grpcCommentClient :=initgRPCCommentClient()
-	storyUsecase := usecase.NewStoryUsecase(storyRepo, grpcCommentClient,categoryRepo)
-	categoryUsecase := usecase.NewCategoryUsecase(categoryRepo)
+	accountClient := initAccountClient()
+	storyUsecase := usecase.NewStoryUsecase(storyRepo, grpcCommentClient,categoryRepo, accountClient, tagRepo, transactor)
+	categoryUsecase := usecase.NewCategoryUsecase(categoryRepo, storyRepo, transactor)
+	tagUsecase := usecase.NewTagUsecase(tagRepo)
+
+	verifier, err := auth.NewVerifier(auth.VerifierOptions{
+		Algorithm:    config.JWTAlgorithm(),
+		Secret:       config.JWTSecret(),
+		PublicKeyPEM: config.JWTPublicKey(),
+		Issuer:       config.JWTIssuer(),
+		Audience:     config.JWTAudience(),
+	})
+	if err != nil {
+		log.Panicf("failed to init jwt verifier, error %v", err)
+	}
 
 	e := echo.New()
+	e.HTTPErrorHandler = handlerHttp.ErrorHandler
+	e.Use(handlerHttp.IdentifyCaller(verifier))
+	authMiddleware := handlerHttp.Authenticate(verifier)
 
-	handlerHttp.NewStoryHandler(e, storyUsecase)
-	handlerHttp.NewCategoryHandler(e, categoryUsecase)
+	cachePolicy := handlerHttp.CachePolicy(config.HTTPCacheControl())
+	handlerHttp.NewStoryHandler(e, storyUsecase, authMiddleware, cachePolicy)
+	handlerHttp.NewCategoryHandler(e, categoryUsecase, authMiddleware, cachePolicy)
+	handlerHttp.NewTagHandler(e, tagUsecase, storyUsecase)
+
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(handlerGrpc.AuthInterceptor(verifier)))
+	pb.RegisterStoryServiceServer(grpcServer, handlerGrpc.NewStoryHandler(storyUsecase))
+	pb.RegisterCategoryServiceServer(grpcServer, handlerGrpc.NewCategoryHandler(categoryUsecase))
+
+	go runPublishScheduler(context.Background(), storyUsecase, config.SchedulerPublishInterval())
+	go runCommentCountScheduler(context.Background(), storyUsecase, config.SchedulerCommentCountInterval())
 
 	var wg sync.WaitGroup
 	errCh := make(chan error, 2)
@@ -56,6 +90,20 @@ func httpServer(cmd *cobra.Command, args []string) {
 			errCh <- err
 		}
 	}()
+
+	go func() {
+		defer wg.Done()
+		listener, err := net.Listen("tcp", ":"+config.GRPCPort())
+		if err != nil {
+			errCh <- err
+			return
+		}
+		log.Printf("grpc server running on port %s", config.GRPCPort())
+		err = grpcServer.Serve(listener)
+		if err != nil {
+			errCh <- err
+		}
+	}()
 	wg.Wait()
 	close(errCh)
 
@@ -66,6 +114,29 @@ func httpServer(cmd *cobra.Command, args []string) {
 	}
 }
 func initgRPCCommentClient() comment_service.CommentServiceClient {
+	return repository.NewCommentClient(dialCommentService(), repository.CommentClientOptions{
+		Timeout:          config.CommentServiceTimeout(),
+		MaxRetries:       config.CommentServiceMaxRetries(),
+		RetryBackoff:     config.CommentServiceRetryBackoff(),
+		BreakerThreshold: config.CommentServiceBreakerThreshold(),
+		BreakerCooldown:  config.CommentServiceBreakerCooldown(),
+	})
+}
+
+func dialCommentService() comment_service.CommentServiceClient {
+	switch mode := config.CommentServiceMode(); mode {
+	case "", "grpc":
+	case "fake":
+		// The fake is served in-process for as long as the server runs
+		client, _, err := fake.NewCommentServer().Dial()
+		if err != nil {
+			log.Panicf("failed to start fake comment service, error %v", err)
+		}
+		return client
+	default:
+		log.Panicf("unknown comment_service.mode %q, expected grpc or fake", mode)
+	}
+
 	// connect to grpc server without credentials
 	conn, err := grpc.NewClient(config.CommentgRPCHost(), grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
@@ -74,3 +145,14 @@ func initgRPCCommentClient() comment_service.CommentServiceClient {
 	// init grpc client as package dependency from grpc-server repository
 	return comment_service.NewCommentServiceClient(conn)
 }
+
+func initAccountClient() model.IAccountClient {
+	switch mode := config.AccountServiceMode(); mode {
+	case "", "http":
+	case "fake":
+		return fake.NewAccountClient()
+	default:
+		log.Panicf("unknown account_service.mode %q, expected http or fake", mode)
+	}
+	return repository.NewAccountRepo(config.AccountServiceBaseURL(), config.AccountServiceTimeout())
+}
@@ -0,0 +1,102 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/helper"
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+	pb "github.com/kodinggo/gb-2-api-story-service/pb/story_service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type CategoryHandler struct {
+	pb.UnimplementedCategoryServiceServer
+	categoryUsecase model.ICategoryUsecase
+}
+
+func NewCategoryHandler(us model.ICategoryUsecase) pb.CategoryServiceServer {
+	return &CategoryHandler{
+		categoryUsecase: us,
+	}
+}
+
+func (ch *CategoryHandler) FindAll(ctx context.Context, _ *emptypb.Empty) (*pb.Categories, error) {
+	categories, err := ch.categoryUsecase.FindAll(ctx, model.FindCategoriesParam{})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &pb.Categories{
+		Categories: helper.ConvertModelCategoriesToPb(categories),
+	}, nil
+}
+
+func (ch *CategoryHandler) FindById(ctx context.Context, req *pb.FindByIdRequest) (*pb.Category, error) {
+	if req.Id < 1 {
+		return nil, status.Error(codes.InvalidArgument, "invalid category id")
+	}
+
+	category, err := ch.categoryUsecase.FindById(ctx, req.Id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return helper.ConvertModelCategoryToPb(category), nil
+}
+
+func (ch *CategoryHandler) Create(ctx context.Context, req *pb.CreateCategoryRequest) (*pb.Category, error) {
+	category, err := ch.categoryUsecase.Create(ctx, model.Categories{
+		Name:        req.Name,
+		ParentId:    parentId(req.ParentId),
+		Description: req.Description,
+		IconUrl:     req.IconUrl,
+		Color:       req.Color,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return helper.ConvertModelCategoryToPb(category), nil
+}
+
+func (ch *CategoryHandler) Update(ctx context.Context, req *pb.UpdateCategoryRequest) (*pb.Category, error) {
+	if req.Id < 1 {
+		return nil, status.Error(codes.InvalidArgument, "invalid category id")
+	}
+
+	category, err := ch.categoryUsecase.Update(ctx, model.Categories{
+		Id:          req.Id,
+		Name:        req.Name,
+		ParentId:    parentId(req.ParentId),
+		Description: req.Description,
+		IconUrl:     req.IconUrl,
+		Color:       req.Color,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return helper.ConvertModelCategoryToPb(category), nil
+}
+
+func (ch *CategoryHandler) Delete(ctx context.Context, req *pb.DeleteRequest) (*emptypb.Empty, error) {
+	if req.Id < 1 {
+		return nil, status.Error(codes.InvalidArgument, "invalid category id")
+	}
+
+	if err := ch.categoryUsecase.Delete(ctx, req.Id, model.DeleteCategoryInput{}); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// parentId maps the zero parent_id of top level categories to nil.
+func parentId(id int64) *int64 {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
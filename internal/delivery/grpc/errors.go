@@ -0,0 +1,43 @@
+package grpc
+
+import (
+	"errors"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatusError converts a usecase error into a gRPC status error. Like the
+// HTTP ErrorHandler, it only passes the messages of expected errors on to
+// callers.
+func toStatusError(err error) error {
+	var validationErr *model.ValidationError
+	if errors.As(err, &validationErr) {
+		if errors.Is(err, model.ErrUnprocessable) {
+			return status.Error(codes.FailedPrecondition, validationErr.Error())
+		}
+		return status.Error(codes.InvalidArgument, validationErr.Error())
+	}
+
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, model.ErrConflict):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, model.ErrPreconditionFailed):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, model.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, model.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, model.ErrUnavailable):
+		logrus.Error(err)
+		return status.Error(codes.Unavailable, "a dependent service is unavailable, please retry later")
+	}
+
+	// Unexpected errors may carry SQL or driver details, so they stay in the logs
+	logrus.Error(err)
+	return status.Error(codes.Internal, "internal server error")
+}
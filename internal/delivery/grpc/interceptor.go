@@ -0,0 +1,37 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/auth"
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AuthInterceptor puts the caller from the bearer token in the request
+// metadata into the request context. Calls without a token pass through
+// anonymously and are rejected by the usecases that need a caller.
+func AuthInterceptor(verifier *auth.Verifier) googlegrpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *googlegrpc.UnaryServerInfo, handler googlegrpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return handler(ctx, req)
+		}
+
+		token, ok := auth.BearerToken(values[0])
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "invalid authorization metadata")
+		}
+
+		caller, err := verifier.Verify(token)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+		}
+
+		return handler(model.NewCallerContext(ctx, caller), req)
+	}
+}
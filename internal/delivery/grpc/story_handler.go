@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/helper"
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+	pb "github.com/kodinggo/gb-2-api-story-service/pb/story_service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type StoryHandler struct {
+	pb.UnimplementedStoryServiceServer
+	storyUsecase model.IStoryUsecase
+}
+
+func NewStoryHandler(us model.IStoryUsecase) pb.StoryServiceServer {
+	return &StoryHandler{
+		storyUsecase: us,
+	}
+}
+
+// FindAll, FindById and FindByIDs leave comments out, so the comment service
+// can call them back without a cycle.
+func (s *StoryHandler) FindAll(ctx context.Context, req *pb.FindAllStoriesRequest) (*pb.Stories, error) {
+	stories, _, err := s.storyUsecase.FindAll(ctx, model.FindAllParam{
+		Limit: req.Limit,
+		Page:  req.Page,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &pb.Stories{
+		Stories: helper.ConvertModelStoriesToPb(stories),
+	}, nil
+}
+
+func (s *StoryHandler) FindById(ctx context.Context, req *pb.FindByIdRequest) (*pb.Story, error) {
+	if req.Id < 1 {
+		return nil, status.Error(codes.InvalidArgument, "invalid story id")
+	}
+
+	story, err := s.storyUsecase.FindById(ctx, req.Id)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return helper.ConvertModelStoryToPb(story), nil
+}
+
+func (s *StoryHandler) FindByIDs(ctx context.Context, req *pb.FindByIDsRequest) (*pb.Stories, error) {
+	stories, err := s.storyUsecase.FindByIDs(ctx, req.Ids)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &pb.Stories{
+		Stories: helper.ConvertModelStoriesToPb(stories),
+	}, nil
+}
+
+func (s *StoryHandler) Create(ctx context.Context, req *pb.CreateStoryRequest) (*pb.Story, error) {
+	var publishAt *time.Time
+	if req.PublishAt != nil {
+		t := req.PublishAt.AsTime()
+		publishAt = &t
+	}
+
+	story, err := s.storyUsecase.Create(ctx, model.CreateStoryInput{
+		Title:        req.Title,
+		Content:      req.Content,
+		ThumbnailUrl: req.ThumbnailUrl,
+		CategoryId:   int(req.CategoryId),
+		Status:       req.Status,
+		PublishAt:    publishAt,
+		Tags:         req.Tags,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return helper.ConvertModelStoryToPb(story), nil
+}
+
+func (s *StoryHandler) Update(ctx context.Context, req *pb.UpdateStoryRequest) (*pb.Story, error) {
+	if req.Id < 1 {
+		return nil, status.Error(codes.InvalidArgument, "invalid story id")
+	}
+
+	story, err := s.storyUsecase.Update(ctx, req.Id, 0, model.UpdateStoryInput{
+		Title:        req.Title,
+		Content:      req.Content,
+		ThumbnailUrl: req.ThumbnailUrl,
+		CategoryId:   int(req.CategoryId),
+		Tags:         req.Tags,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return helper.ConvertModelStoryToPb(story), nil
+}
+
+func (s *StoryHandler) Delete(ctx context.Context, req *pb.DeleteRequest) (*emptypb.Empty, error) {
+	if req.Id < 1 {
+		return nil, status.Error(codes.InvalidArgument, "invalid story id")
+	}
+
+	if err := s.storyUsecase.Delete(ctx, req.Id, 0); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Route names used as keys of CachePolicy.
+const (
+	CacheRouteStory      = "story"
+	CacheRouteCategories = "categories"
+	CacheRouteCategory   = "category"
+)
+
+// CachePolicy holds the Cache-Control header sent by each cacheable route.
+// Routes without an entry send no Cache-Control header.
+type CachePolicy map[string]string
+
+// cachedJSON writes body as a successful JSON response carrying ETag and
+// Last-Modified validators, or 304 Not Modified when the request's
+// If-None-Match or If-Modified-Since header shows the client is up to date.
+func cachedJSON(c echo.Context, cacheControl string, lastModified time.Time, etag func([]byte) string, body any) error {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	tag := etag(raw)
+	header := c.Response().Header()
+	header.Set(headerETag, tag)
+	if !lastModified.IsZero() {
+		header.Set(echo.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+	if cacheControl != "" {
+		header.Set(echo.HeaderCacheControl, cacheControl)
+	}
+
+	if notModified(c.Request(), tag, lastModified) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.JSONBlob(http.StatusOK, raw)
+}
+
+// notModified evaluates the conditional GET headers. If-None-Match takes
+// precedence over If-Modified-Since, as required by RFC 9110.
+func notModified(req *http.Request, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := req.Header.Get(headerIfNoneMatch); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if lastModified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(req.Header.Get(echo.HeaderIfModifiedSince))
+	if err != nil {
+		return false
+	}
+
+	// HTTP dates have a one second resolution
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// bodyETag derives a strong ETag from the response body alone.
+func bodyETag(body []byte) string {
+	return fmt.Sprintf(`"%s"`, bodyHash(body))
+}
+
+func bodyHash(body []byte) string {
+	h := fnv.New64a()
+	h.Write(body)
+	return fmt.Sprintf("%016x", h.Sum64())
+}
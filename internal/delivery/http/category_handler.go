@@ -1,8 +1,11 @@
 package http
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/kodinggo/gb-2-api-story-service/internal/model"
 	"github.com/labstack/echo/v4"
@@ -10,33 +13,56 @@ import (
 
 type CategoryHandler struct {
 	categoryUsecase model.ICategoryUsecase
+	cachePolicy     CachePolicy
 }
 
-func NewCategoryHandler(e *echo.Echo, us model.ICategoryUsecase) {
+func NewCategoryHandler(e *echo.Echo, us model.ICategoryUsecase, authMiddleware echo.MiddlewareFunc, cachePolicy CachePolicy) {
 	handlers := &CategoryHandler{
 		categoryUsecase: us,
+		cachePolicy:     cachePolicy,
 	}
 
 	routeCategories := e.Group("/v1/categories")
 	routeCategories.GET("", handlers.GetCategories)
 	routeCategories.GET("/:id", handlers.GetCategory)
-	routeCategories.POST("", handlers.CreateCategory)
-	routeCategories.PUT("/:id", handlers.UpdateCategory)
-	routeCategories.DELETE("/:id", handlers.DeleteCategory)
+	routeCategories.GET("/slug/:slug", handlers.GetCategoryBySlug)
+	routeCategories.POST("", handlers.CreateCategory, authMiddleware)
+	routeCategories.PUT("/:id", handlers.UpdateCategory, authMiddleware)
+	routeCategories.PUT("/order", handlers.ReorderCategories, authMiddleware)
+	routeCategories.PATCH("/:id", handlers.PatchCategory, authMiddleware)
+	routeCategories.DELETE("/:id", handlers.DeleteCategory, authMiddleware)
 
 }
 
 func (ch *CategoryHandler) GetCategories(c echo.Context) error {
-	categories, err := ch.categoryUsecase.FindAll(c.Request().Context())
+	var param model.FindCategoriesParam
+	for _, with := range strings.Split(c.QueryParam("with"), ",") {
+		switch strings.TrimSpace(with) {
+		case "":
+		case "stats":
+			param.WithStats = true
+		default:
+			return model.NewValidationError("with", "must be a list of: stats")
+		}
+	}
 
+	var categories []*model.Categories
+	var err error
+	switch c.QueryParam("format") {
+	case "", "flat":
+		categories, err = ch.categoryUsecase.FindAll(c.Request().Context(), param)
+	case "tree":
+		categories, err = ch.categoryUsecase.FindTree(c.Request().Context(), param)
+	default:
+		return model.NewValidationError("format", "must be one of: flat tree")
+	}
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, response{
-			Status:  http.StatusInternalServerError,
-			Message: err.Error(),
-		})
+		return err
 	}
 
-	return c.JSON(http.StatusOK, response{
+	// Deleted categories and stats leave no trace in the remaining updated_at
+	// values, so validation is left to the ETag
+	return cachedJSON(c, ch.cachePolicy[CacheRouteCategories], time.Time{}, bodyETag, response{
 		Status: "success",
 		Data:   categories,
 	})
@@ -46,18 +72,35 @@ func (s *CategoryHandler) GetCategory(c echo.Context) error {
 	id := c.Param("id")
 	parsedId, err := strconv.Atoi(id)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, response{
-			Status:  http.StatusBadRequest,
-			Message: err.Error(),
-		})
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid category ID")
 	}
 
 	category, err := s.categoryUsecase.FindById(c.Request().Context(), int64(parsedId))
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "category not found")
+		return err
 	}
 
-	return c.JSON(http.StatusOK, response{
+	return cachedJSON(c, s.cachePolicy[CacheRouteCategory], category.UpdatedAt, bodyETag, response{
+		Status:  http.StatusOK,
+		Message: "Success",
+		Data:    category,
+	})
+}
+
+func (s *CategoryHandler) GetCategoryBySlug(c echo.Context) error {
+	slug := c.Param("slug")
+
+	category, err := s.categoryUsecase.FindBySlug(c.Request().Context(), slug)
+	if err != nil {
+		return err
+	}
+
+	// The category was found by an old slug
+	if category.Slug != slug {
+		return c.Redirect(http.StatusMovedPermanently, "/v1/categories/slug/"+category.Slug)
+	}
+
+	return cachedJSON(c, s.cachePolicy[CacheRouteCategory], category.UpdatedAt, bodyETag, response{
 		Status:  http.StatusOK,
 		Message: "Success",
 		Data:    category,
@@ -70,12 +113,22 @@ func (s *CategoryHandler) CreateCategory(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid input")
 	}
 
-	if err := s.categoryUsecase.Create(c.Request().Context(), model.Categories{Name: input.Name}); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create category")
+	category, err := s.categoryUsecase.Create(c.Request().Context(), model.Categories{
+		Name:        input.Name,
+		ParentId:    input.ParentId,
+		Description: input.Description,
+		IconUrl:     input.IconUrl,
+		Color:       input.Color,
+		Position:    input.Position,
+	})
+	if err != nil {
+		return err
 	}
 
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/v1/categories/%d", category.Id))
 	return c.JSON(http.StatusCreated, response{
 		Status: "success",
+		Data:   category,
 	})
 }
 
@@ -95,12 +148,61 @@ func (s *CategoryHandler) UpdateCategory(c echo.Context) error {
 		in.Id = int64(parsedId)
 	}
 
-	if err := s.categoryUsecase.Update(c.Request().Context(), model.Categories{Id: in.Id, Name: in.Name}); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update category")
+	category, err := s.categoryUsecase.Update(c.Request().Context(), model.Categories{
+		Id:          in.Id,
+		Name:        in.Name,
+		ParentId:    in.ParentId,
+		Description: in.Description,
+		IconUrl:     in.IconUrl,
+		Color:       in.Color,
+		Position:    in.Position,
+	})
+	if err != nil {
+		return err
 	}
 
 	return c.JSON(http.StatusOK, response{
 		Status: "success",
+		Data:   category,
+	})
+}
+
+func (s *CategoryHandler) PatchCategory(c echo.Context) error {
+	parsedId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid category ID")
+	}
+
+	var in model.PatchCategoryInput
+	if err := c.Bind(&in); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid input")
+	}
+
+	category, err := s.categoryUsecase.Patch(c.Request().Context(), int64(parsedId), in)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response{
+		Status: "success",
+		Data:   category,
+	})
+}
+
+func (s *CategoryHandler) ReorderCategories(c echo.Context) error {
+	var in model.ReorderCategoriesInput
+	if err := c.Bind(&in); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid input")
+	}
+
+	categories, err := s.categoryUsecase.Reorder(c.Request().Context(), in)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response{
+		Status: "success",
+		Data:   categories,
 	})
 }
 
@@ -108,11 +210,20 @@ func (s *CategoryHandler) DeleteCategory(c echo.Context) error {
 	id := c.Param("id")
 	parsedId, err := strconv.Atoi(id)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid category ID")
+	}
+
+	var in model.DeleteCategoryInput
+	if reassignToParam := c.QueryParam("reassign_to"); reassignToParam != "" {
+		reassignTo, err := strconv.ParseInt(reassignToParam, 10, 64)
+		if err != nil || reassignTo <= 0 {
+			return model.NewValidationError("reassign_to", "must be a positive number")
+		}
+		in.ReassignTo = reassignTo
 	}
 
-	if err := s.categoryUsecase.Delete(c.Request().Context(), int64(parsedId)); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	if err := s.categoryUsecase.Delete(c.Request().Context(), int64(parsedId), in); err != nil {
+		return err
 	}
 
 	return c.JSON(http.StatusNoContent, response{
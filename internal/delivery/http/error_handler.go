@@ -0,0 +1,79 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrorHandler is the echo HTTPErrorHandler. It maps domain errors returned by
+// the handlers to their status code and writes them as a uniform JSON body.
+func ErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	code, body := errorResponse(err)
+	if code >= http.StatusInternalServerError {
+		logrus.WithFields(logrus.Fields{
+			"method": c.Request().Method,
+			"path":   c.Path(),
+		}).Error(err)
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(code)
+	} else {
+		err = c.JSON(code, body)
+	}
+
+	if err != nil {
+		logrus.Error(err)
+	}
+}
+
+func errorResponse(err error) (int, response) {
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		message, ok := httpErr.Message.(string)
+		if !ok {
+			message = http.StatusText(httpErr.Code)
+		}
+		return httpErr.Code, response{Status: httpErr.Code, Message: message}
+	}
+
+	var validationErr *model.ValidationError
+	if errors.As(err, &validationErr) {
+		code := http.StatusBadRequest
+		if errors.Is(err, model.ErrUnprocessable) {
+			code = http.StatusUnprocessableEntity
+		}
+		return code, response{
+			Status:  code,
+			Message: "Invalid input",
+			Errors:  validationErr.Fields,
+		}
+	}
+
+	code := http.StatusInternalServerError
+	message := "Internal server error"
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		code, message = http.StatusNotFound, err.Error()
+	case errors.Is(err, model.ErrConflict):
+		code, message = http.StatusConflict, err.Error()
+	case errors.Is(err, model.ErrPreconditionFailed):
+		code, message = http.StatusPreconditionFailed, err.Error()
+	case errors.Is(err, model.ErrUnauthenticated):
+		code, message = http.StatusUnauthorized, "Authentication required"
+	case errors.Is(err, model.ErrForbidden):
+		code, message = http.StatusForbidden, "You are not allowed to perform this action"
+	case errors.Is(err, model.ErrUnavailable):
+		code, message = http.StatusServiceUnavailable, "A dependent service is unavailable, please retry later"
+	}
+
+	return code, response{Status: code, Message: message}
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+func TestErrorHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    int
+		message string
+		field   string
+	}{
+		{
+			name:    "missing resource",
+			err:     model.NewNotFoundError("story"),
+			code:    http.StatusNotFound,
+			message: "story not found",
+		},
+		{
+			name:    "reference to a missing resource",
+			err:     model.NewUnprocessableError("category_id", "refers to a category that does not exist"),
+			code:    http.StatusUnprocessableEntity,
+			message: "Invalid input",
+			field:   "category_id",
+		},
+		{
+			name:    "invalid input",
+			err:     model.NewValidationError("title", "is required"),
+			code:    http.StatusBadRequest,
+			message: "Invalid input",
+			field:   "title",
+		},
+	}
+
+	e := echo.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+			ErrorHandler(tt.err, c)
+
+			if rec.Code != tt.code {
+				t.Fatalf("expected status %d, got %d", tt.code, rec.Code)
+			}
+
+			var body response
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Message != tt.message {
+				t.Errorf("expected message %q, got %q", tt.message, body.Message)
+			}
+			if tt.field != "" && body.Errors[tt.field] == "" {
+				t.Errorf("expected an error for %s, got %v", tt.field, body.Errors)
+			}
+		})
+	}
+}
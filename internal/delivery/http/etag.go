@@ -0,0 +1,57 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	headerETag        = "ETag"
+	headerIfMatch     = "If-Match"
+	headerIfNoneMatch = "If-None-Match"
+)
+
+// storyETag derives a strong ETag from the story version.
+func storyETag(story *model.Story) string {
+	return fmt.Sprintf(`"%d-%d"`, story.Id, story.Version)
+}
+
+// storyRepresentationETag extends storyETag with a hash of the response body,
+// so that changes outside the story row, such as new comments, still produce a
+// new tag. parseIfMatch accepts both forms.
+func storyRepresentationETag(story *model.Story) func([]byte) string {
+	return func(body []byte) string {
+		return fmt.Sprintf(`"%d-%d-%s"`, story.Id, story.Version, bodyHash(body))
+	}
+}
+
+// parseIfMatch returns the story version required by the If-Match header. A
+// missing header or "*" returns zero, meaning the write is unconditional.
+func parseIfMatch(c echo.Context, id int64) (int64, error) {
+	header := strings.TrimSpace(c.Request().Header.Get(headerIfMatch))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	prefix := fmt.Sprintf(`"%d-`, id)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if !strings.HasPrefix(tag, prefix) || !strings.HasSuffix(tag, `"`) {
+			continue
+		}
+
+		rawVersion, _, _ := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(tag, prefix), `"`), "-")
+		version, err := strconv.ParseInt(rawVersion, 10, 64)
+		if err == nil && version > 0 {
+			return version, nil
+		}
+	}
+
+	// None of the given tags can ever match this story
+	return 0, echo.NewHTTPError(http.StatusPreconditionFailed, "If-Match does not match the current story version")
+}
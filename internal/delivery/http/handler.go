@@ -1,7 +1,11 @@
 package http
 
+import "github.com/kodinggo/gb-2-api-story-service/internal/model"
+
 type response struct {
-	Status  any         `json:"status"`
-	Message string      `json:"message,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
+	Status     any               `json:"status"`
+	Message    string            `json:"message,omitempty"`
+	Data       interface{}       `json:"data,omitempty"`
+	Errors     map[string]string `json:"errors,omitempty"`
+	Pagination *model.Pagination `json:"pagination,omitempty"`
 }
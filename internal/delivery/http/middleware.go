@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/auth"
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+// Authenticate rejects requests without a valid bearer token and puts the
+// caller from the token into the request context.
+func Authenticate(verifier *auth.Verifier) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := auth.BearerToken(c.Request().Header.Get(echo.HeaderAuthorization))
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Missing bearer token")
+			}
+
+			caller, err := verifier.Verify(token)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid or expired token")
+			}
+
+			ctx := model.NewCallerContext(c.Request().Context(), caller)
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
+
+// IdentifyCaller puts the caller into the request context when the request
+// carries a valid bearer token, and lets anonymous requests through. Routes
+// that require a caller still need Authenticate.
+func IdentifyCaller(verifier *auth.Verifier) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := auth.BearerToken(c.Request().Header.Get(echo.HeaderAuthorization))
+			if !ok {
+				return next(c)
+			}
+
+			caller, err := verifier.Verify(token)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid or expired token")
+			}
+
+			ctx := model.NewCallerContext(c.Request().Context(), caller)
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *StoryHandler) GetTrash(c echo.Context) error {
+	param, err := bindFindAllParam(c)
+	if err != nil {
+		return err
+	}
+
+	stories, pagination, err := s.storyUsecase.FindTrash(c.Request().Context(), param.Limit, param.Page)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response{
+		Status:     "success",
+		Data:       stories,
+		Pagination: pagination,
+	})
+}
+
+func (s *StoryHandler) RestoreDeletedStory(c echo.Context) error {
+	storyId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid story ID")
+	}
+
+	var categoryId int64
+	if categoryIdParam := c.QueryParam("category_id"); categoryIdParam != "" {
+		categoryId, err = strconv.ParseInt(categoryIdParam, 10, 64)
+		if err != nil || categoryId <= 0 {
+			return model.NewValidationError("category_id", "must be a positive number")
+		}
+	}
+
+	story, err := s.storyUsecase.RestoreDeleted(c.Request().Context(), storyId, categoryId)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(headerETag, storyETag(story))
+	return c.JSON(http.StatusOK, response{
+		Status:  "success",
+		Message: "Success Restore Story",
+		Data:    story,
+	})
+}
+
+func (s *StoryHandler) PurgeStory(c echo.Context) error {
+	storyId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid story ID")
+	}
+
+	if err := s.storyUsecase.Purge(c.Request().Context(), storyId); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusNoContent, response{
+		Status: http.StatusNoContent,
+	})
+}
@@ -1,8 +1,12 @@
 package http
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/kodinggo/gb-2-api-story-service/internal/model"
 	"github.com/labstack/echo/v4"
@@ -10,28 +14,83 @@ import (
 
 type StoryHandler struct {
 	storyUsecase model.IStoryUsecase
+	cachePolicy  CachePolicy
 }
 
-func NewStoryHandler(e *echo.Echo, us model.IStoryUsecase) {
+func NewStoryHandler(e *echo.Echo, us model.IStoryUsecase, authMiddleware echo.MiddlewareFunc, cachePolicy CachePolicy) {
 	handlers := &StoryHandler{
 		storyUsecase: us,
+		cachePolicy:  cachePolicy,
 	}
 
 	routeStories := e.Group("/v1/stories")
 	routeStories.GET("", handlers.GetStories)
 	routeStories.GET("/:id", handlers.GetStory)
-	routeStories.POST("", handlers.CreateStory)
-	routeStories.PUT("/:id", handlers.UpdateStory)
-	routeStories.DELETE("/:id", handlers.DeleteStory)
+	routeStories.GET("/slug/:slug", handlers.GetStoryBySlug)
+	routeStories.POST("", handlers.CreateStory, authMiddleware)
+	routeStories.PUT("/:id", handlers.UpdateStory, authMiddleware)
+	routeStories.PATCH("/:id", handlers.PatchStory, authMiddleware)
+	routeStories.DELETE("/:id", handlers.DeleteStory, authMiddleware)
+	routeStories.PUT("/:id/status", handlers.ChangeStoryStatus, authMiddleware)
+	routeStories.GET("/:id/comments", handlers.GetStoryComments)
+	routeStories.GET("/:id/revisions", handlers.GetStoryRevisions, authMiddleware)
+	routeStories.GET("/:id/revisions/:rev", handlers.GetStoryRevision, authMiddleware)
+	routeStories.POST("/:id/revisions/:rev/restore", handlers.RestoreStoryRevision, authMiddleware)
+
+	e.GET("/v1/users/:id/stories", handlers.GetUserStories)
+
+	routeAdmin := e.Group("/v1/admin/stories", authMiddleware)
+	routeAdmin.GET("/trash", handlers.GetTrash)
+	routeAdmin.POST("/:id/restore", handlers.RestoreDeletedStory)
+	routeAdmin.DELETE("/:id/purge", handlers.PurgeStory)
 }
 
 func (s *StoryHandler) GetStories(c echo.Context) error {
+	param, err := bindFindAllParam(c)
+	if err != nil {
+		return err
+	}
+
+	return s.findStories(c, param)
+}
+
+func (s *StoryHandler) GetUserStories(c echo.Context) error {
+	userId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || userId <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID")
+	}
+
+	param, err := bindFindAllParam(c)
+	if err != nil {
+		return err
+	}
+	param.UserID = userId
+
+	return s.findStories(c, param)
+}
+
+func (s *StoryHandler) findStories(c echo.Context, param model.FindAllParam) error {
+	stories, pagination, err := s.storyUsecase.FindAll(c.Request().Context(), param)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response{
+		Status:     "success",
+		Data:       stories,
+		Pagination: pagination,
+	})
+}
+
+// bindFindAllParam reads the listing query parameters shared by every story
+// listing endpoint.
+func bindFindAllParam(c echo.Context) (model.FindAllParam, error) {
 	var param model.FindAllParam
 
 	if limitParam := c.QueryParam("limit"); limitParam != "" {
 		parsedLimit, err := strconv.Atoi(limitParam)
 		if err != nil || parsedLimit <= 0 {
-			return echo.NewHTTPError(http.StatusBadRequest, "Invalid limit value")
+			return param, model.NewValidationError("limit", "must be a positive number")
 		}
 		param.Limit = int64(parsedLimit)
 	}
@@ -39,20 +98,93 @@ func (s *StoryHandler) GetStories(c echo.Context) error {
 	if pageParam := c.QueryParam("page"); pageParam != "" {
 		parsedPage, err := strconv.Atoi(pageParam)
 		if err != nil || parsedPage <= 0 {
-			return echo.NewHTTPError(http.StatusBadRequest, "Invalid page value")
+			return param, model.NewValidationError("page", "must be a positive number")
 		}
 		param.Page = int64(parsedPage)
 	}
 
-	stories, err := s.storyUsecase.FindAll(c.Request().Context(), param)
+	param.Query = strings.TrimSpace(c.QueryParam("q"))
+	param.Sort = c.QueryParam("sort")
+	param.Status = c.QueryParam("status")
+	param.Tag = c.QueryParam("tag")
+
+	categoryIDs, err := parseIDList(c.QueryParams()["category_id"])
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Error fetching stories")
+		return param, model.NewValidationError("category_id", "must be a list of positive numbers")
 	}
+	param.CategoryIDs = categoryIDs
 
-	return c.JSON(http.StatusOK, response{
-		Status: "success",
-		Data:   stories,
-	})
+	if includeDescendantsParam := c.QueryParam("include_descendants"); includeDescendantsParam != "" {
+		includeDescendants, err := strconv.ParseBool(includeDescendantsParam)
+		if err != nil {
+			return param, model.NewValidationError("include_descendants", "must be a boolean")
+		}
+		param.IncludeDescendants = includeDescendants
+	}
+
+	if userIdParam := c.QueryParam("user_id"); userIdParam != "" {
+		parsedUserId, err := strconv.ParseInt(userIdParam, 10, 64)
+		if err != nil || parsedUserId <= 0 {
+			return param, model.NewValidationError("user_id", "must be a positive number")
+		}
+		param.UserID = parsedUserId
+	}
+
+	if createdFromParam := c.QueryParam("created_from"); createdFromParam != "" {
+		createdFrom, err := parseDateParam(createdFromParam, false)
+		if err != nil {
+			return param, model.NewValidationError("created_from", "must be a RFC 3339 timestamp or a YYYY-MM-DD date")
+		}
+		param.CreatedFrom = &createdFrom
+	}
+
+	if createdToParam := c.QueryParam("created_to"); createdToParam != "" {
+		createdTo, err := parseDateParam(createdToParam, true)
+		if err != nil {
+			return param, model.NewValidationError("created_to", "must be a RFC 3339 timestamp or a YYYY-MM-DD date")
+		}
+		param.CreatedTo = &createdTo
+	}
+
+	if param.CreatedFrom != nil && param.CreatedTo != nil && param.CreatedTo.Before(*param.CreatedFrom) {
+		return param, model.NewValidationError("created_to", "must not be before created_from")
+	}
+
+	if cursorParam := c.QueryParam("cursor"); cursorParam != "" {
+		cursor, err := model.DecodeStoryCursor(cursorParam)
+		if err != nil {
+			return param, model.ErrInvalidCursor
+		}
+		param.Cursor = cursor
+	}
+
+	if includeTotalParam := c.QueryParam("include_total"); includeTotalParam != "" {
+		includeTotal, err := strconv.ParseBool(includeTotalParam)
+		if err != nil {
+			return param, model.NewValidationError("include_total", "must be a boolean")
+		}
+		param.WithTotal = includeTotal
+	}
+
+	for _, include := range strings.Split(c.QueryParam("include"), ",") {
+		switch strings.TrimSpace(include) {
+		case "":
+		case "comments":
+			param.IncludeComments = true
+		default:
+			return param, model.NewValidationError("include", "must be a list of: comments")
+		}
+	}
+
+	if commentsLimitParam := c.QueryParam("comments_limit"); commentsLimitParam != "" {
+		commentsLimit, err := strconv.ParseInt(commentsLimitParam, 10, 64)
+		if err != nil || commentsLimit <= 0 {
+			return param, model.NewValidationError("comments_limit", "must be a positive number")
+		}
+		param.CommentsLimit = commentsLimit
+	}
+
+	return param, nil
 }
 
 func (s *StoryHandler) GetStory(c echo.Context) error {
@@ -65,10 +197,39 @@ func (s *StoryHandler) GetStory(c echo.Context) error {
 
 	story, err := s.storyUsecase.FindById(c.Request().Context(), int64(parsedId))
 	if err != nil {
-		return echo.NewHTTPError(http.StatusNotFound, "Sorry, story not found!")
+		return err
 	}
 
-	return c.JSON(http.StatusOK, response{
+	return s.writeStory(c, story)
+}
+
+func (s *StoryHandler) GetStoryBySlug(c echo.Context) error {
+	slug := c.Param("slug")
+
+	story, err := s.storyUsecase.FindBySlug(c.Request().Context(), slug)
+	if err != nil {
+		return err
+	}
+
+	// The story was found by an old slug
+	if story.Slug != slug {
+		return c.Redirect(http.StatusMovedPermanently, "/v1/stories/slug/"+story.Slug)
+	}
+
+	return s.writeStory(c, story)
+}
+
+// writeStory sends a single story with its cache headers.
+func (s *StoryHandler) writeStory(c echo.Context, story *model.Story) error {
+	// Unpublished stories are only shown to their author and staff
+	cacheControl := s.cachePolicy[CacheRouteStory]
+	if story.Status != model.StoryStatusPublished {
+		cacheControl = "private, no-cache"
+	}
+
+	// Comments and tags change without touching updated_at, so validation is
+	// left to the ETag
+	return cachedJSON(c, cacheControl, time.Time{}, storyRepresentationETag(story), response{
 		Status: "success",
 		Data:   story,
 	})
@@ -81,13 +242,17 @@ func (s *StoryHandler) CreateStory(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	if err := s.storyUsecase.Create(c.Request().Context(), input); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create story")
+	story, err := s.storyUsecase.Create(c.Request().Context(), input)
+	if err != nil {
+		return err
 	}
 
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/v1/stories/%d", story.Id))
+	c.Response().Header().Set(headerETag, storyETag(story))
 	return c.JSON(http.StatusCreated, response{
 		Status:  "success",
 		Message: "Success Create Story",
+		Data:    story,
 	})
 }
 
@@ -99,18 +264,84 @@ func (s *StoryHandler) UpdateStory(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid story ID")
 	}
 
+	version, err := parseIfMatch(c, storyId)
+	if err != nil {
+		return err
+	}
+
 	var input model.UpdateStoryInput
 	if err := c.Bind(&input); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid input")
 	}
 
-	if err := s.storyUsecase.Update(c.Request().Context(), storyId, input); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update story")
+	story, err := s.storyUsecase.Update(c.Request().Context(), storyId, version, input)
+	if err != nil {
+		return err
 	}
 
+	c.Response().Header().Set(headerETag, storyETag(story))
 	return c.JSON(http.StatusOK, response{
 		Status:  "success",
 		Message: "Success Update Story",
+		Data:    story,
+	})
+}
+
+func (s *StoryHandler) PatchStory(c echo.Context) error {
+	storyId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid story ID")
+	}
+
+	version, err := parseIfMatch(c, storyId)
+	if err != nil {
+		return err
+	}
+
+	var input model.PatchStoryInput
+	if err := c.Bind(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid input")
+	}
+
+	story, err := s.storyUsecase.Patch(c.Request().Context(), storyId, version, input)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(headerETag, storyETag(story))
+	return c.JSON(http.StatusOK, response{
+		Status:  "success",
+		Message: "Success Update Story",
+		Data:    story,
+	})
+}
+
+func (s *StoryHandler) ChangeStoryStatus(c echo.Context) error {
+	storyId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid story ID")
+	}
+
+	version, err := parseIfMatch(c, storyId)
+	if err != nil {
+		return err
+	}
+
+	var input model.StoryStatusInput
+	if err := c.Bind(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid input")
+	}
+
+	story, err := s.storyUsecase.ChangeStatus(c.Request().Context(), storyId, version, input)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(headerETag, storyETag(story))
+	return c.JSON(http.StatusOK, response{
+		Status:  "success",
+		Message: "Success Change Story Status",
+		Data:    story,
 	})
 }
 
@@ -118,14 +349,161 @@ func (s *StoryHandler) DeleteStory(c echo.Context) error {
 	id := c.Param("id")
 	parsedId, err := strconv.Atoi(id)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid story ID")
+	}
+
+	version, err := parseIfMatch(c, int64(parsedId))
+	if err != nil {
+		return err
 	}
 
-	if err := s.storyUsecase.Delete(c.Request().Context(), int64(parsedId)); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	if err := s.storyUsecase.Delete(c.Request().Context(), int64(parsedId), version); err != nil {
+		return err
 	}
 
 	return c.JSON(http.StatusNoContent, response{
 		Status: http.StatusNoContent,
 	})
 }
+
+// GetStoryComments pages through the comments of a story, newest first.
+func (s *StoryHandler) GetStoryComments(c echo.Context) error {
+	storyId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid story ID")
+	}
+
+	var param model.FindCommentsParam
+	if limitParam := c.QueryParam("limit"); limitParam != "" {
+		limit, err := strconv.ParseInt(limitParam, 10, 64)
+		if err != nil || limit <= 0 {
+			return model.NewValidationError("limit", "must be a positive number")
+		}
+		param.Limit = limit
+	}
+
+	if cursorParam := c.QueryParam("cursor"); cursorParam != "" {
+		cursor, err := model.DecodeCommentCursor(cursorParam)
+		if err != nil {
+			return model.ErrInvalidCursor
+		}
+		param.Cursor = cursor
+	}
+
+	comments, pagination, err := s.storyUsecase.FindComments(c.Request().Context(), storyId, param)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response{
+		Status:     "success",
+		Data:       comments,
+		Pagination: pagination,
+	})
+}
+
+func (s *StoryHandler) GetStoryRevisions(c echo.Context) error {
+	storyId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid story ID")
+	}
+
+	revisions, err := s.storyUsecase.FindRevisions(c.Request().Context(), storyId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response{
+		Status: "success",
+		Data:   revisions,
+	})
+}
+
+func (s *StoryHandler) GetStoryRevision(c echo.Context) error {
+	storyId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid story ID")
+	}
+
+	revision, err := strconv.ParseInt(c.Param("rev"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid revision")
+	}
+
+	storyRevision, err := s.storyUsecase.FindRevision(c.Request().Context(), storyId, revision)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response{
+		Status: "success",
+		Data:   storyRevision,
+	})
+}
+
+func (s *StoryHandler) RestoreStoryRevision(c echo.Context) error {
+	storyId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid story ID")
+	}
+
+	revision, err := strconv.ParseInt(c.Param("rev"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid revision")
+	}
+
+	version, err := parseIfMatch(c, storyId)
+	if err != nil {
+		return err
+	}
+
+	story, err := s.storyUsecase.RestoreRevision(c.Request().Context(), storyId, revision, version)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(headerETag, storyETag(story))
+	return c.JSON(http.StatusOK, response{
+		Status:  "success",
+		Message: "Success Restore Story",
+		Data:    story,
+	})
+}
+
+// parseIDList parses ids given either as repeated query parameters or as a
+// comma separated list, e.g. ?category_id=1&category_id=2 or ?category_id=1,2.
+func parseIDList(values []string) ([]int64, error) {
+	var ids []int64
+	for _, value := range values {
+		for _, raw := range strings.Split(value, ",") {
+			raw = strings.TrimSpace(raw)
+			if raw == "" {
+				continue
+			}
+			id, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil || id <= 0 {
+				return nil, errors.New("invalid id")
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// parseDateParam accepts RFC 3339 timestamps or plain YYYY-MM-DD dates. A plain
+// date used as an upper bound covers the whole day.
+func parseDateParam(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+type TagHandler struct {
+	tagUsecase   model.ITagUsecase
+	storyUsecase model.IStoryUsecase
+}
+
+func NewTagHandler(e *echo.Echo, tagUsecase model.ITagUsecase, storyUsecase model.IStoryUsecase) {
+	handlers := &TagHandler{
+		tagUsecase:   tagUsecase,
+		storyUsecase: storyUsecase,
+	}
+
+	routeTags := e.Group("/v1/tags")
+	routeTags.GET("", handlers.GetTags)
+	routeTags.GET("/:slug/stories", handlers.GetTagStories)
+}
+
+func (t *TagHandler) GetTags(c echo.Context) error {
+	tags, err := t.tagUsecase.FindAll(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response{
+		Status: "success",
+		Data:   tags,
+	})
+}
+
+// GetTagStories lists the stories with the tag, taking the same query
+// parameters as the story listing.
+func (t *TagHandler) GetTagStories(c echo.Context) error {
+	tag, err := t.tagUsecase.FindBySlug(c.Request().Context(), c.Param("slug"))
+	if err != nil {
+		return err
+	}
+
+	param, err := bindFindAllParam(c)
+	if err != nil {
+		return err
+	}
+	param.Tag = tag.Slug
+
+	stories, pagination, err := t.storyUsecase.FindAll(c.Request().Context(), param)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response{
+		Status:     "success",
+		Data:       stories,
+		Pagination: pagination,
+	})
+}
@@ -0,0 +1,50 @@
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+)
+
+// AccountClient is an in-memory model.IAccountClient for local development
+// and tests.
+type AccountClient struct {
+	mu       sync.RWMutex
+	accounts map[int64]*model.Account
+}
+
+func NewAccountClient(accounts ...model.Account) *AccountClient {
+	client := &AccountClient{
+		accounts: make(map[int64]*model.Account),
+	}
+
+	for _, account := range accounts {
+		client.Add(account)
+	}
+
+	return client
+}
+
+// Add stores an account, replacing any account with the same ID.
+func (a *AccountClient) Add(account model.Account) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.accounts[account.Id] = &account
+}
+
+func (a *AccountClient) FindByIDs(_ context.Context, ids []int64) (map[int64]*model.Account, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	accounts := make(map[int64]*model.Account, len(ids))
+	for _, id := range ids {
+		if account, ok := a.accounts[id]; ok {
+			copied := *account
+			accounts[id] = &copied
+		}
+	}
+
+	return accounts, nil
+}
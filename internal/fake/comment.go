@@ -0,0 +1,127 @@
+package fake
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/kodinggo/gb-2-api-comment-service/pb/comment_service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// commentBufferSize is the buffer of the in-process connection to the fake.
+const commentBufferSize = 1024 * 1024
+
+// CommentServer is an in-memory comment_service.CommentServiceServer for local
+// development and tests. Dial serves it in-process, so story usecases can be
+// run end to end without the comment service.
+type CommentServer struct {
+	comment_service.UnimplementedCommentServiceServer
+
+	mu       sync.RWMutex
+	comments []*comment_service.Comment
+	nextId   int64
+	err      error
+}
+
+func NewCommentServer(comments ...*comment_service.Comment) *CommentServer {
+	server := &CommentServer{}
+
+	for _, comment := range comments {
+		server.Add(comment)
+	}
+
+	return server
+}
+
+// Add stores a copy of the comment. Comments without an ID or creation time
+// are given one.
+func (s *CommentServer) Add(comment *comment_service.Comment) *comment_service.Comment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := proto.Clone(comment).(*comment_service.Comment)
+	if stored.Id == 0 {
+		s.nextId++
+		stored.Id = s.nextId
+	} else if stored.Id > s.nextId {
+		s.nextId = stored.Id
+	}
+	if stored.CreatedAt == nil {
+		stored.CreatedAt = timestamppb.Now()
+	}
+
+	s.comments = append(s.comments, stored)
+
+	return proto.Clone(stored).(*comment_service.Comment)
+}
+
+// Fail makes every call return err, e.g. a status error with codes.Unavailable
+// to simulate an outage. A nil err makes calls succeed again.
+func (s *CommentServer) Fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+}
+
+func (s *CommentServer) FindAllByStoryID(_ context.Context, req *comment_service.FindAllByStoryIDRequest) (*comment_service.Comments, error) {
+	return s.find(req.StoryId)
+}
+
+func (s *CommentServer) FindAllByStoryIDs(_ context.Context, req *comment_service.FindAllByStoryIDsRequest) (*comment_service.Comments, error) {
+	return s.find(req.StoryId...)
+}
+
+func (s *CommentServer) find(storyIds ...int64) (*comment_service.Comments, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	wanted := make(map[int64]bool, len(storyIds))
+	for _, storyId := range storyIds {
+		wanted[storyId] = true
+	}
+
+	res := &comment_service.Comments{}
+	for _, comment := range s.comments {
+		if wanted[comment.StoryId] {
+			res.Comments = append(res.Comments, proto.Clone(comment).(*comment_service.Comment))
+		}
+	}
+
+	return res, nil
+}
+
+// Dial serves the fake over an in-process bufconn listener and returns a
+// client connected to it, along with a func that closes the client and stops
+// the server.
+func (s *CommentServer) Dial() (comment_service.CommentServiceClient, func(), error) {
+	listener := bufconn.Listen(commentBufferSize)
+	server := grpc.NewServer()
+	comment_service.RegisterCommentServiceServer(server, s)
+	go server.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		server.Stop()
+		return nil, nil, err
+	}
+
+	return comment_service.NewCommentServiceClient(conn), func() {
+		conn.Close()
+		server.Stop()
+	}, nil
+}
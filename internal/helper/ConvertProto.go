@@ -3,6 +3,8 @@ package helper
 import (
 	"github.com/kodinggo/gb-2-api-comment-service/pb/comment_service"
 	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+	"github.com/kodinggo/gb-2-api-story-service/pb/story_service"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 
@@ -10,11 +12,85 @@ func ConvertPbCommentToModelComments(pbcomment []*comment_service.Comment)[]*mod
 
 	var comments []*model.Comment
 	for _, pbComment := range pbcomment {
-		comments = append(comments, &model.Comment{
+		comment := &model.Comment{
 			ID: pbComment.Id,
 			Comment: pbComment.Comment,
 			StoryID: pbComment.StoryId,
-		})
+		}
+		if pbComment.CreatedAt != nil {
+			comment.CreatedAt = pbComment.CreatedAt.AsTime()
+		}
+		if pbComment.UpdatedAt != nil {
+			updatedAt := pbComment.UpdatedAt.AsTime()
+			comment.UpdatedAt = &updatedAt
+		}
+		if pbComment.Author != nil {
+			comment.UserID = pbComment.Author.Id
+		}
+		comments = append(comments, comment)
 	}
 	return comments
-}
\ No newline at end of file
+}
+
+func ConvertModelStoryToPb(story *model.Story) *story_service.Story {
+	pbStory := &story_service.Story{
+		Id:           story.Id,
+		Title:        story.Title,
+		Slug:         story.Slug,
+		Content:      story.Content,
+		ThumbnailUrl: story.ThumbnailUrl,
+		Category: &story_service.Category{
+			Id:   story.Category.Id,
+			Name: story.Category.Name,
+			Slug: story.Category.Slug,
+		},
+		CreatedAt: timestamppb.New(story.CreatedAt),
+		UpdatedAt: timestamppb.New(story.UpdatedAt),
+		Status:    story.Status,
+	}
+	if story.PublishedAt != nil {
+		pbStory.PublishedAt = timestamppb.New(*story.PublishedAt)
+	}
+	for _, tag := range story.Tags {
+		pbStory.Tags = append(pbStory.Tags, tag.Name)
+	}
+
+	return pbStory
+}
+
+func ConvertModelStoriesToPb(stories []*model.Story) []*story_service.Story {
+	var pbStories []*story_service.Story
+	for _, story := range stories {
+		pbStories = append(pbStories, ConvertModelStoryToPb(story))
+	}
+	return pbStories
+}
+
+func ConvertModelCategoryToPb(category *model.Categories) *story_service.Category {
+	pbCategory := &story_service.Category{
+		Id:          category.Id,
+		Name:        category.Name,
+		Slug:        category.Slug,
+		Description: category.Description,
+		IconUrl:     category.IconUrl,
+		Color:       category.Color,
+		CreatedAt:   timestamppb.New(category.CreatedAt),
+		UpdatedAt:   timestamppb.New(category.UpdatedAt),
+	}
+	if category.ParentId != nil {
+		pbCategory.ParentId = *category.ParentId
+	}
+	if category.Position != nil {
+		pbCategory.Position = *category.Position
+	}
+
+	return pbCategory
+}
+
+func ConvertModelCategoriesToPb(categories []*model.Categories) []*story_service.Category {
+	var pbCategories []*story_service.Category
+	for _, category := range categories {
+		pbCategories = append(pbCategories, ConvertModelCategoryToPb(category))
+	}
+	return pbCategories
+}
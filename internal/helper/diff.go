@@ -0,0 +1,158 @@
+package helper
+
+import (
+	"strings"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+)
+
+// maxDiffLines caps the combined number of lines DiffLines compares, which
+// bounds the time spent on a single diff.
+const maxDiffLines = 10000
+
+// DiffLines returns the line-level changes that turn from into to, using
+// Myers' algorithm in linear space. It reports false when the texts together
+// have more than maxDiffLines lines.
+func DiffLines(from, to string) ([]model.DiffLine, bool) {
+	a := splitLines(from)
+	b := splitLines(to)
+	if len(a)+len(b) > maxDiffLines {
+		return nil, false
+	}
+
+	return diffLines(a, b, make([]model.DiffLine, 0, max(len(a), len(b)))), true
+}
+
+// diffLines appends the changes that turn a into b to diff.
+func diffLines(a, b []string, diff []model.DiffLine) []model.DiffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		diff = append(diff, model.DiffLine{Op: model.DiffEqual, Text: a[prefix]})
+		prefix++
+	}
+	a, b = a[prefix:], b[prefix:]
+
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-suffix-1] == b[len(b)-suffix-1] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0:
+		diff = appendDiff(diff, model.DiffInsert, b)
+	case len(b) == 0:
+		diff = appendDiff(diff, model.DiffDelete, a)
+	default:
+		if x, y, ok := middleSnake(a, b); ok {
+			diff = diffLines(a[:x], b[:y], diff)
+			diff = diffLines(a[x:], b[y:], diff)
+		} else {
+			diff = appendDiff(diff, model.DiffDelete, a)
+			diff = appendDiff(diff, model.DiffInsert, b)
+		}
+	}
+
+	return appendDiff(diff, model.DiffEqual, common)
+}
+
+// middleSnake runs Myers' search from both ends of a and b at once and returns
+// the point where the two paths meet, which splits the diff into two smaller
+// ones. It reports false when a and b have no line in common.
+func middleSnake(a, b []string) (int, int, bool) {
+	n, m := len(a), len(b)
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+
+	// forward[k] and backward[k] are the furthest x reached on diagonal k,
+	// counted from the start and from the end respectively
+	forward := make([]int, 2*offset+1)
+	backward := make([]int, 2*offset+1)
+	for i := range forward {
+		forward[i] = -1
+		backward[i] = -1
+	}
+	forward[offset+1] = 0
+	backward[offset+1] = 0
+
+	delta := n - m
+	// With an odd delta the paths can only meet on a forward step
+	odd := delta%2 != 0
+
+	var fStart, fEnd, bStart, bEnd int
+	for d := 0; d < maxD; d++ {
+		for k := -d + fStart; k <= d-fEnd; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+
+			switch {
+			case x > n:
+				fEnd += 2
+			case y > m:
+				fStart += 2
+			case odd:
+				i := offset + delta - k
+				if i >= 0 && i < len(backward) && backward[i] != -1 && x >= n-backward[i] {
+					return x, y, true
+				}
+			}
+		}
+
+		for k := -d + bStart; k <= d-bEnd; k += 2 {
+			var x int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[n-x-1] == b[m-y-1] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+
+			switch {
+			case x > n:
+				bEnd += 2
+			case y > m:
+				bStart += 2
+			case !odd:
+				i := offset + delta - k
+				if i >= 0 && i < len(forward) && forward[i] != -1 {
+					fx := forward[i]
+					if fx >= n-x {
+						return fx, fx - (delta - k), true
+					}
+				}
+			}
+		}
+	}
+
+	return 0, 0, false
+}
+
+func appendDiff(diff []model.DiffLine, op string, lines []string) []model.DiffLine {
+	for _, line := range lines {
+		diff = append(diff, model.DiffLine{Op: op, Text: line})
+	}
+	return diff
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,146 @@
+package helper
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want []model.DiffLine
+	}{
+		{
+			name: "equal",
+			from: "a\nb",
+			to:   "a\nb",
+			want: []model.DiffLine{{Op: model.DiffEqual, Text: "a"}, {Op: model.DiffEqual, Text: "b"}},
+		},
+		{
+			name: "from empty",
+			from: "",
+			to:   "a",
+			want: []model.DiffLine{{Op: model.DiffInsert, Text: "a"}},
+		},
+		{
+			name: "to empty",
+			from: "a",
+			to:   "",
+			want: []model.DiffLine{{Op: model.DiffDelete, Text: "a"}},
+		},
+		{
+			name: "changed line",
+			from: "a\nb\nc",
+			to:   "a\nx\nc",
+			want: []model.DiffLine{
+				{Op: model.DiffEqual, Text: "a"},
+				{Op: model.DiffDelete, Text: "b"},
+				{Op: model.DiffInsert, Text: "x"},
+				{Op: model.DiffEqual, Text: "c"},
+			},
+		},
+		{
+			name: "moved line",
+			from: "a\nb\nc\nd",
+			to:   "b\nc\nd\na",
+			want: []model.DiffLine{
+				{Op: model.DiffDelete, Text: "a"},
+				{Op: model.DiffEqual, Text: "b"},
+				{Op: model.DiffEqual, Text: "c"},
+				{Op: model.DiffEqual, Text: "d"},
+				{Op: model.DiffInsert, Text: "a"},
+			},
+		},
+		{
+			name: "windows line endings",
+			from: "a\r\nb",
+			to:   "a\nb",
+			want: []model.DiffLine{{Op: model.DiffEqual, Text: "a"}, {Op: model.DiffEqual, Text: "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DiffLines(tt.from, tt.to)
+			if !ok {
+				t.Fatal("expected a diff")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDiffLinesTooLarge(t *testing.T) {
+	text := strings.Repeat("line\n", maxDiffLines)
+
+	if diff, ok := DiffLines(text, text); ok || diff != nil {
+		t.Fatalf("expected no diff past %d lines", maxDiffLines)
+	}
+}
+
+// TestDiffLinesRandom checks random edits rebuild both texts with as few
+// changes as the longest common subsequence allows.
+func TestDiffLinesRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		lines := make([]string, r.Intn(30))
+		for i := range lines {
+			lines[i] = string(rune('a' + r.Intn(4)))
+		}
+		return lines
+	}
+
+	for i := 0; i < 500; i++ {
+		a, b := randomLines(), randomLines()
+		diff, ok := DiffLines(strings.Join(a, "\n"), strings.Join(b, "\n"))
+		if !ok {
+			t.Fatal("expected a diff")
+		}
+
+		var from, to []string
+		changes := 0
+		for _, line := range diff {
+			if line.Op != model.DiffInsert {
+				from = append(from, line.Text)
+			}
+			if line.Op != model.DiffDelete {
+				to = append(to, line.Text)
+			}
+			if line.Op != model.DiffEqual {
+				changes++
+			}
+		}
+
+		if strings.Join(from, "\n") != strings.Join(a, "\n") || strings.Join(to, "\n") != strings.Join(b, "\n") {
+			t.Fatalf("diff of %q and %q does not rebuild them: %v", a, b, diff)
+		}
+		if want := len(a) + len(b) - 2*lcsLength(a, b); changes != want {
+			t.Fatalf("diff of %q and %q has %d changes, expected %d", a, b, changes, want)
+		}
+	}
+}
+
+func lcsLength(a, b []string) int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	return lcs[0][0]
+}
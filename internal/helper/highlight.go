@@ -0,0 +1,96 @@
+package helper
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	highlightOpenTag  = "<mark>"
+	highlightCloseTag = "</mark>"
+)
+
+// SearchTerms splits a full-text query into the words worth highlighting,
+// dropping MySQL boolean operators and very short words.
+func SearchTerms(query string) []string {
+	var terms []string
+	for _, word := range strings.Fields(query) {
+		word = strings.Trim(word, `+-<>()~*"'@`)
+		if utf8.RuneCountInString(word) < 2 {
+			continue
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// Highlight HTML-escapes text and wraps every occurrence of the given terms in
+// <mark> tags, matching case-insensitively.
+func Highlight(text string, terms []string) string {
+	pattern := termsPattern(terms)
+	if pattern == nil {
+		return html.EscapeString(text)
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range pattern.FindAllStringIndex(text, -1) {
+		b.WriteString(html.EscapeString(text[last:loc[0]]))
+		b.WriteString(highlightOpenTag)
+		b.WriteString(html.EscapeString(text[loc[0]:loc[1]]))
+		b.WriteString(highlightCloseTag)
+		last = loc[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+
+	return b.String()
+}
+
+// Snippet returns a highlighted excerpt of at most size runes centred on the
+// first matched term. When nothing matches, the beginning of text is used.
+func Snippet(text string, terms []string, size int) string {
+	runes := []rune(text)
+	if len(runes) <= size {
+		return Highlight(text, terms)
+	}
+
+	start := 0
+	if pattern := termsPattern(terms); pattern != nil {
+		if loc := pattern.FindStringIndex(text); loc != nil {
+			start = utf8.RuneCountInString(text[:loc[0]]) - size/2
+		}
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := start + size
+	if end > len(runes) {
+		end = len(runes)
+		start = end - size
+	}
+
+	snippet := Highlight(string(runes[start:end]), terms)
+	if start > 0 {
+		snippet = "..." + snippet
+	}
+	if end < len(runes) {
+		snippet += "..."
+	}
+
+	return snippet
+}
+
+func termsPattern(terms []string) *regexp.Regexp {
+	if len(terms) == 0 {
+		return nil
+	}
+
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		quoted = append(quoted, regexp.QuoteMeta(term))
+	}
+
+	return regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+}
@@ -0,0 +1,68 @@
+package helper
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/gosimple/unidecode"
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxSlugLength keeps slugs well inside the 255 character column, leaving
+// room for de-duplication suffixes.
+const maxSlugLength = 200
+
+// slugReplacements transliterates characters that read better as words than
+// as the unidecode transliteration.
+var slugReplacements = map[rune]string{
+	'&': "and",
+}
+
+// Slugify turns text into a lowercase, hyphen separated ASCII slug, e.g.
+// "Crème Brûlée & Café" becomes "creme-brulee-and-cafe" and "Привет мир"
+// becomes "privet-mir". Letters of other scripts are transliterated with
+// unidecode; characters without a transliteration, such as emoji, are
+// dropped, so the result may be empty.
+func Slugify(text string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	write := func(part string) {
+		for _, r := range part {
+			if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+				pendingHyphen = b.Len() > 0
+				continue
+			}
+
+			if pendingHyphen {
+				b.WriteByte('-')
+				pendingHyphen = false
+			}
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+
+	for _, r := range norm.NFKD.String(text) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+
+		if replacement, ok := slugReplacements[r]; ok {
+			// Keep words apart, so "R&D" does not become "randd"
+			write(" " + replacement + " ")
+		} else if r > unicode.MaxASCII {
+			write(unidecode.Unidecode(string(r)))
+		} else {
+			write(string(r))
+		}
+	}
+
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		slug = slug[:maxSlugLength]
+		if i := strings.LastIndexByte(slug, '-'); i > 0 {
+			slug = slug[:i]
+		}
+	}
+
+	return strings.Trim(slug, "-")
+}
@@ -0,0 +1,48 @@
+package helper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "ascii", text: "Hello World", want: "hello-world"},
+		{name: "accents", text: "Crème Brûlée & Café", want: "creme-brulee-and-cafe"},
+		{name: "cyrillic", text: "Привет мир", want: "privet-mir"},
+		{name: "han", text: "北京欢迎你", want: "bei-jing-huan-ying-ni"},
+		{name: "ampersand between words", text: "R&D", want: "r-and-d"},
+		{name: "punctuation collapses", text: "  Go -- 1.22!  ", want: "go-1-22"},
+		{name: "compatibility form", text: "ﬁle №1", want: "file-no1"},
+		{name: "emoji only", text: "🎉🚀", want: ""},
+		{name: "empty", text: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.text); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugifyTruncates(t *testing.T) {
+	// The limit falls inside the 34th word, which is dropped whole
+	text := strings.Repeat("abcde ", 40)
+
+	got := Slugify(text)
+	if len(got) > maxSlugLength {
+		t.Fatalf("expected at most %d characters, got %d", maxSlugLength, len(got))
+	}
+	if strings.HasSuffix(got, "-") {
+		t.Errorf("expected no trailing hyphen, got %q", got)
+	}
+	if want := strings.TrimSuffix(strings.Repeat("abcde-", 33), "-"); got != want {
+		t.Errorf("expected the slug to be cut at a word boundary, got %q", got)
+	}
+}
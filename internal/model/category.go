@@ -6,34 +6,113 @@ import (
 )
 
 type ICategoryUsecase interface {
-	FindAll(ctx context.Context) ([]*Categories, error)
+	FindAll(ctx context.Context, param FindCategoriesParam) ([]*Categories, error)
+	FindTree(ctx context.Context, param FindCategoriesParam) ([]*Categories, error)
 	FindById(ctx context.Context, id int64) (*Categories, error)
-	Create(ctx context.Context, category Categories) error
-	Update(ctx context.Context, category Categories) error
-	Delete(ctx context.Context, id int64) error
+	FindBySlug(ctx context.Context, slug string) (*Categories, error)
+	Create(ctx context.Context, category Categories) (*Categories, error)
+	Update(ctx context.Context, category Categories) (*Categories, error)
+	Patch(ctx context.Context, id int64, in PatchCategoryInput) (*Categories, error)
+	Reorder(ctx context.Context, in ReorderCategoriesInput) ([]*Categories, error)
+	Delete(ctx context.Context, id int64, in DeleteCategoryInput) error
 }
 
 type ICategoryRepository interface {
 	FindAll(ctx context.Context) ([]*Categories, error)
+	FindAllWithStats(ctx context.Context) ([]*Categories, error)
 	FindById(ctx context.Context, id int64) (*Categories, error)
-	Create(ctx context.Context, category Categories) error
-	Update(ctx context.Context, category Categories) error
+	FindByIdForUpdate(ctx context.Context, id int64) (*Categories, error)
+	FindByIdForShare(ctx context.Context, id int64) (*Categories, error)
+	FindBySlug(ctx context.Context, slug string) (*Categories, error)
+	Create(ctx context.Context, category Categories) (*Categories, error)
+	Update(ctx context.Context, category Categories) (*Categories, error)
+	Patch(ctx context.Context, id int64, in PatchCategoryInput) (*Categories, error)
 	Delete(ctx context.Context, id int64) error
+	FindSlugRedirect(ctx context.Context, slug string) (int64, error)
+	MoveChildren(ctx context.Context, fromParentId int64, toParentId *int64) error
+	UpdatePosition(ctx context.Context, id int64, position int64) error
+	RefreshStats(ctx context.Context, ids ...int64) error
+	FindAncestorIDs(ctx context.Context, id int64) ([]int64, error)
 }
 
+// Categories form a tree through ParentId. Children is only filled in when
+// categories are returned as a tree.
 type Categories struct {
-	Id        int64     `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Id          int64          `json:"id"`
+	Name        string         `json:"name" validate:"required,max=255"`
+	Slug        string         `json:"slug"`
+	ParentId    *int64         `json:"parent_id" validate:"omitempty,gt=0"`
+	Description string         `json:"description" validate:"max=1000"`
+	IconUrl     string         `json:"icon_url" validate:"omitempty,url,max=255"`
+	Color       string         `json:"color" validate:"omitempty,hexcolor,max=7"`
+	Position    *int64         `json:"position" validate:"omitempty,gte=0"`
+	Stats       *CategoryStats `json:"stats,omitempty"`
+	Children    []*Categories  `json:"children,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// CategoryStats summarises the published stories of a category. The values
+// are cached on the category and refreshed whenever its stories change.
+type CategoryStats struct {
+	StoryCount    int64      `json:"story_count"`
+	LatestStoryAt *time.Time `json:"latest_story_at"`
+}
+
+type FindCategoriesParam struct {
+	WithStats bool
 }
 
 type CreateCategoryInput struct {
-	Name string `json:"name" validate:"required"`
+	Name        string `json:"name" validate:"required"`
+	ParentId    *int64 `json:"parent_id"`
+	Description string `json:"description"`
+	IconUrl     string `json:"icon_url"`
+	Color       string `json:"color"`
+	Position    *int64 `json:"position"`
 }
 
 type UpdateCategoryInput struct {
-	Id       int64      `json:"id"`
-	Name     string     `json:"name" validate:"required"`
-	UpdateAt *time.Time `json:"updated_at"`
+	Id          int64      `json:"id"`
+	Name        string     `json:"name" validate:"required"`
+	ParentId    *int64     `json:"parent_id"`
+	Description string     `json:"description"`
+	IconUrl     string     `json:"icon_url"`
+	Color       string     `json:"color"`
+	Position    *int64     `json:"position"`
+	UpdateAt    *time.Time `json:"updated_at"`
+}
+
+// PatchCategoryInput changes only the fields that are present in the request.
+// A ParentId of 0 makes the category a top level one.
+type PatchCategoryInput struct {
+	Name        *string `json:"name" validate:"omitempty,min=1,max=255"`
+	ParentId    *int64  `json:"parent_id" validate:"omitempty,gte=0"`
+	Description *string `json:"description" validate:"omitempty,max=1000"`
+	IconUrl     *string `json:"icon_url" validate:"omitempty,url,max=255"`
+	Color       *string `json:"color" validate:"omitempty,hexcolor,max=7"`
+	Position    *int64  `json:"position" validate:"omitempty,gte=0"`
+}
+
+// IsEmpty reports whether the patch changes nothing.
+func (in PatchCategoryInput) IsEmpty() bool {
+	return in.Name == nil && in.ParentId == nil && in.Description == nil && in.IconUrl == nil && in.Color == nil && in.Position == nil
+}
+
+// ReorderCategoriesInput sets the display position of several categories at
+// once.
+type ReorderCategoriesInput struct {
+	Positions []CategoryPosition `json:"positions" validate:"required,min=1,unique=Id,dive"`
+}
+
+type CategoryPosition struct {
+	Id       int64 `json:"id" validate:"required,gt=0"`
+	Position int64 `json:"position" validate:"gte=0"`
+}
+
+// DeleteCategoryInput decides what happens to the stories of a deleted
+// category. Without ReassignTo, deleting a category that still has stories is
+// rejected.
+type DeleteCategoryInput struct {
+	ReassignTo int64 `json:"reassign_to" validate:"omitempty,gt=0"`
 }
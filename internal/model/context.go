@@ -0,0 +1,52 @@
+package model
+
+import (
+	"context"
+	"slices"
+)
+
+// Roles granted to callers through their access token.
+const (
+	RoleAdmin  = "admin"
+	RoleEditor = "editor"
+)
+
+// Caller is the authenticated user a request is made on behalf of.
+type Caller struct {
+	UserID int64
+	Roles  []string
+}
+
+// HasRole reports whether the caller was granted any of the given roles.
+func (c *Caller) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		if slices.Contains(c.Roles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+type ctxKey string
+
+const callerCtxKey ctxKey = "caller"
+
+// NewCallerContext returns a copy of ctx carrying the authenticated caller.
+func NewCallerContext(ctx context.Context, caller *Caller) context.Context {
+	return context.WithValue(ctx, callerCtxKey, caller)
+}
+
+// CallerFromContext returns the authenticated caller, if any.
+func CallerFromContext(ctx context.Context) (*Caller, bool) {
+	caller, ok := ctx.Value(callerCtxKey).(*Caller)
+	return caller, ok && caller != nil && caller.UserID > 0
+}
+
+// UserIDFromContext returns the ID of the authenticated caller, if any.
+func UserIDFromContext(ctx context.Context) (int64, bool) {
+	caller, ok := CallerFromContext(ctx)
+	if !ok {
+		return 0, false
+	}
+	return caller.UserID, true
+}
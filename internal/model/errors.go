@@ -0,0 +1,65 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrConflict        = errors.New("conflict")
+	ErrUnauthenticated = errors.New("unauthenticated")
+	ErrForbidden       = errors.New("forbidden")
+	ErrUnavailable     = errors.New("upstream service unavailable")
+	ErrUnprocessable   = errors.New("unprocessable entity")
+
+	// ErrPreconditionFailed is returned when a conditional write finds the
+	// resource changed since the client last read it.
+	ErrPreconditionFailed = errors.New("resource was modified by another request")
+)
+
+// NewNotFoundError reports that the given resource does not exist, e.g.
+// "story not found".
+func NewNotFoundError(resource string) error {
+	return fmt.Errorf("%s %w", resource, ErrNotFound)
+}
+
+// NewConflictError reports that the request conflicts with the current state
+// of a resource.
+func NewConflictError(message string) error {
+	return fmt.Errorf("%w: %s", ErrConflict, message)
+}
+
+// NewUnavailableError reports that an upstream service could not be reached.
+func NewUnavailableError(service string, err error) error {
+	return fmt.Errorf("%s: %w: %v", service, ErrUnavailable, err)
+}
+
+// NewUnprocessableError reports well-formed input that cannot be applied, such
+// as a reference to a resource that does not exist.
+func NewUnprocessableError(field, message string) error {
+	return errors.Join(ErrUnprocessable, NewValidationError(field, message))
+}
+
+// ValidationError reports invalid input with a message per offending field.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func NewValidationError(field, message string) *ValidationError {
+	return &ValidationError{
+		Fields: map[string]string{field: message},
+	}
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field, message := range e.Fields {
+		fields = append(fields, field+" "+message)
+	}
+	sort.Strings(fields)
+
+	return "validation failed: " + strings.Join(fields, ", ")
+}
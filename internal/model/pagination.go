@@ -0,0 +1,74 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+var ErrInvalidCursor = NewValidationError("cursor", "is invalid or does not match the sort order")
+
+// Pagination is the metadata returned alongside a page of results.
+type Pagination struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+// StoryCursor points at a story in a listing ordered by (created_at, id).
+// Backward cursors fetch the page before the story instead of after it.
+type StoryCursor struct {
+	CreatedAt time.Time `json:"t"`
+	Id        int64     `json:"id"`
+	Backward  bool      `json:"b,omitempty"`
+}
+
+// Encode returns the opaque form of the cursor handed out to clients.
+func (c StoryCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeStoryCursor parses a cursor previously produced by StoryCursor.Encode.
+func DecodeStoryCursor(s string) (*StoryCursor, error) {
+	var cursor StoryCursor
+	if err := decodeCursor(s, &cursor); err != nil || cursor.Id <= 0 || cursor.CreatedAt.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+// CommentCursor points at a comment in a listing ordered by (created_at, id),
+// newest first.
+type CommentCursor struct {
+	CreatedAt time.Time `json:"t"`
+	Id        int64     `json:"id"`
+}
+
+// Encode returns the opaque form of the cursor handed out to clients.
+func (c CommentCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCommentCursor parses a cursor previously produced by
+// CommentCursor.Encode.
+func DecodeCommentCursor(s string) (*CommentCursor, error) {
+	var cursor CommentCursor
+	if err := decodeCursor(s, &cursor); err != nil || cursor.Id <= 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+func decodeCursor(s string, cursor any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, cursor)
+}
@@ -2,47 +2,128 @@ package model
 
 import (
 	"context"
-	"database/sql"
 	"time"
 )
 
 const (
 	DefaultLimit = 20
 	DefaultPage  = 1
+
+	// DefaultCommentsLimit is the number of latest comments embedded in each
+	// story of a listing that includes comments
+	DefaultCommentsLimit = 3
+
+	// MaxFindByIDs is the number of stories FindByIDs looks up at once
+	MaxFindByIDs = 100
+)
+
+// Story lifecycle statuses. Only published stories are public.
+const (
+	StoryStatusDraft     = "draft"
+	StoryStatusScheduled = "scheduled"
+	StoryStatusPublished = "published"
+	StoryStatusArchived  = "archived"
+)
+
+// Sort orders accepted by the story listing.
+const (
+	SortNewest        = "newest"
+	SortOldest        = "oldest"
+	SortTitle         = "title"
+	SortUpdated       = "updated"
+	SortMostCommented = "most_commented"
 )
 
 type IStoryRepository interface {
 	FindAll(ctx context.Context, filter FindAllParam) ([]*Story, error)
+	Count(ctx context.Context, filter FindAllParam) (int64, error)
 	FindById(ctx context.Context, id int64) (*Story, error)
-	Create(ctx context.Context, story Story) error
-	Update(ctx context.Context, story Story) error
-	Delete(ctx context.Context, id int64) error
+	FindByIDs(ctx context.Context, ids []int64) ([]*Story, error)
+	FindBySlug(ctx context.Context, slug string) (*Story, error)
+	FindSlugRedirect(ctx context.Context, slug string) (int64, error)
+	Create(ctx context.Context, story Story) (*Story, error)
+	Update(ctx context.Context, story Story) (*Story, error)
+	Patch(ctx context.Context, id int64, version int64, in PatchStoryInput) (*Story, error)
+	Touch(ctx context.Context, id int64, version int64) (*Story, error)
+	Delete(ctx context.Context, id int64, version int64) error
+	UpdateCommentCounts(ctx context.Context, counts map[int64]int64) error
+	FindCommentCounts(ctx context.Context, afterId int64, limit int64) (map[int64]int64, int64, error)
+	FindRevisions(ctx context.Context, storyId int64) ([]*StoryRevision, error)
+	FindRevision(ctx context.Context, storyId int64, revision int64) (*StoryRevision, error)
+	UpdateStatus(ctx context.Context, id int64, version int64, status string, publishedAt *time.Time) (*Story, error)
+	PublishDue(ctx context.Context, now time.Time) (int64, error)
+	FindTrash(ctx context.Context, limit int64, offset int64) ([]*Story, error)
+	Restore(ctx context.Context, id int64, categoryId int64) (*Story, error)
+	Purge(ctx context.Context, id int64) error
+	PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error)
+	CountByCategory(ctx context.Context, categoryId int64) (int64, error)
+	ReassignCategory(ctx context.Context, fromCategoryId int64, toCategoryId int64) (int64, error)
 }
 
+// IStoryUsecase reads and writes stories. FindById, FindBySlug, FindByIDs and
+// FindAll without IncludeComments never call the comment service, so it can
+// call them back, e.g. to validate story IDs, without a cycle.
 type IStoryUsecase interface {
-	FindAll(ctx context.Context, filter FindAllParam) ([]*Story, error)
+	FindAll(ctx context.Context, filter FindAllParam) ([]*Story, *Pagination, error)
 	FindById(ctx context.Context, id int64) (*Story, error)
-	Create(ctx context.Context, in CreateStoryInput) error
-	Update(ctx context.Context, id int64, in UpdateStoryInput) error
-	Delete(ctx context.Context, id int64) error
+	FindByIDs(ctx context.Context, ids []int64) ([]*Story, error)
+	FindBySlug(ctx context.Context, slug string) (*Story, error)
+	Create(ctx context.Context, in CreateStoryInput) (*Story, error)
+	Update(ctx context.Context, id int64, version int64, in UpdateStoryInput) (*Story, error)
+	Patch(ctx context.Context, id int64, version int64, in PatchStoryInput) (*Story, error)
+	Delete(ctx context.Context, id int64, version int64) error
+	FindComments(ctx context.Context, storyId int64, param FindCommentsParam) ([]*Comment, *Pagination, error)
+	FindRevisions(ctx context.Context, storyId int64) ([]*StoryRevision, error)
+	FindRevision(ctx context.Context, storyId int64, revision int64) (*StoryRevision, error)
+	RestoreRevision(ctx context.Context, storyId int64, revision int64, version int64) (*Story, error)
+	ChangeStatus(ctx context.Context, id int64, version int64, in StoryStatusInput) (*Story, error)
+	PublishScheduled(ctx context.Context) (int64, error)
+	SyncCommentCounts(ctx context.Context) (int64, error)
+	FindTrash(ctx context.Context, limit int64, page int64) ([]*Story, *Pagination, error)
+	RestoreDeleted(ctx context.Context, id int64, categoryId int64) (*Story, error)
+	Purge(ctx context.Context, id int64) error
+	PurgeExpired(ctx context.Context, retention time.Duration) (int64, error)
 }
 
+// Story is a published or draft story. CommentsUnavailable is set, and
+// Comments left out, when the comment service could not be reached.
 type Story struct {
-	Id           int64        `json:"id"`
-	Title        string       `json:"title"`
-	Content      string       `json:"content"`
-	ThumbnailUrl string       `json:"thumbnail_url"`
-	Comments     []*Comment   `json:"comments"`
-	Category     Category     `json:"category"`
-	Author       Account      `json:"author"`
-	CreatedAt    time.Time    `json:"created_at"`
-	UpdatedAt    time.Time    `json:"updated_at"`
-	DeletedAt    sql.NullTime `json:"-"`
+	Id           int64      `json:"id"`
+	Title        string     `json:"title"`
+	Slug         string     `json:"slug"`
+	Content      string     `json:"content"`
+	ThumbnailUrl string     `json:"thumbnail_url"`
+	Comments     []*Comment `json:"comments,omitempty"`
+	CommentCount int64      `json:"comment_count"`
+	Version      int64      `json:"version"`
+	Status       string     `json:"status"`
+	PublishedAt  *time.Time `json:"published_at"`
+	Category     Category   `json:"category"`
+	Tags         []*Tag     `json:"tags"`
+	Author       Account    `json:"author"`
+	Highlight    *Highlight `json:"highlight,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+
+	CommentsUnavailable bool `json:"comments_unavailable,omitempty"`
 }
-type AccountUsecase interface {
-	FindByID(id int64, db string) (*Account, error)
+
+// Highlight holds the parts of a story matching a search query, with the
+// matched terms wrapped in <mark> tags.
+type Highlight struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// IAccountClient resolves story authors from the account service. FindByIDs
+// returns the accounts it could resolve even when some lookups fail.
+type IAccountClient interface {
+	FindByIDs(ctx context.Context, ids []int64) (map[int64]*Account, error)
 }
 
+// Account is a story author as shown on public story responses. Email is
+// private to the account and never serialized.
 type Account struct {
 	Id         int64  `json:"id"`
 	Fullname   string `json:"fullname"`
@@ -50,31 +131,87 @@ type Account struct {
 	Gender     string `json:"gender"`
 	PictureUrl string `json:"picture_url"`
 	Username   string `json:"username"`
-	Email      string `json:"email"`
+	Email      string `json:"-"`
 }
 
 type Category struct {
 	Id   int64  `json:"id"`
 	Name string `json:"name"`
+	Slug string `json:"slug"`
 }
 
 type FindAllParam struct {
-	Limit int64
-	Page  int64
+	Limit       int64
+	Page        int64
+	Offset      int64
+	Query       string
+	CategoryIDs []int64
+	UserID      int64
+	Status      string `json:"status" validate:"omitempty,oneof=draft scheduled published archived"`
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Sort        string `json:"sort" validate:"omitempty,oneof=newest oldest title updated most_commented"`
+	Cursor      *StoryCursor
+	WithTotal   bool
+
+	// IncludeDescendants extends CategoryIDs with all their subcategories
+	IncludeDescendants bool
+
+	// Tag limits the listing to stories with the tag of this slug
+	Tag string
+
+	// IncludeComments embeds the latest CommentsLimit comments of each story.
+	// Otherwise stories only carry their comment count.
+	IncludeComments bool
+	CommentsLimit   int64 `json:"comments_limit" validate:"omitempty,min=1,max=20"`
+}
+
+// FindCommentsParam pages through the comments of a story, newest first.
+type FindCommentsParam struct {
+	Limit  int64 `json:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor *CommentCursor
 }
 
 type CreateStoryInput struct {
-	Title        string `json:"title" validate:"required,min=3,max=255"`
-	Content      string `json:"content" validate:"required"`
-	ThumbnailUrl string `json:"thumbnail_url" validate:"required"`
-	CategoryId   int    `json:"category_id" validate:"required"`
+	Title        string     `json:"title" validate:"required,min=3,max=255"`
+	Content      string     `json:"content" validate:"required"`
+	ThumbnailUrl string     `json:"thumbnail_url" validate:"required"`
+	CategoryId   int        `json:"category_id" validate:"required"`
+	Status       string     `json:"status" validate:"omitempty,oneof=draft scheduled published"`
+	PublishAt    *time.Time `json:"publish_at" validate:"required_if=Status scheduled"`
+	Tags         []string   `json:"tags" validate:"omitempty,dive,required,max=50"`
 }
 
+// StoryStatusInput moves a story to another lifecycle status. PublishAt is
+// the publication time of scheduled stories.
+type StoryStatusInput struct {
+	Status    string     `json:"status" validate:"required,oneof=draft scheduled published archived"`
+	PublishAt *time.Time `json:"publish_at" validate:"required_if=Status scheduled"`
+}
+
+// UpdateStoryInput replaces a story. Tags are left unchanged when omitted and
+// cleared when given as an empty list.
 type UpdateStoryInput struct {
-	Title        string `json:"title" validate:"required,min=3,max=255"`
-	Content      string `json:"content" validate:"required"`
-	ThumbnailUrl string `json:"thumbnail_url" validate:"required"`
-	CategoryId   int    `json:"category_id" validate:"required"`
+	Title        string   `json:"title" validate:"required,min=3,max=255"`
+	Content      string   `json:"content" validate:"required"`
+	ThumbnailUrl string   `json:"thumbnail_url" validate:"required"`
+	CategoryId   int      `json:"category_id" validate:"required"`
+	Tags         []string `json:"tags" validate:"omitempty,dive,required,max=50"`
+}
+
+// PatchStoryInput changes only the fields that are present in the request.
+type PatchStoryInput struct {
+	Title        *string  `json:"title" validate:"omitempty,min=3,max=255"`
+	Content      *string  `json:"content" validate:"omitempty,min=1"`
+	ThumbnailUrl *string  `json:"thumbnail_url" validate:"omitempty,min=1"`
+	CategoryId   *int     `json:"category_id" validate:"omitempty,min=1"`
+	Tags         []string `json:"tags" validate:"omitempty,dive,required,max=50"`
+}
+
+// IsEmpty reports whether the patch changes no story columns. Tags are stored
+// separately and are not covered.
+func (in PatchStoryInput) IsEmpty() bool {
+	return in.Title == nil && in.Content == nil && in.ThumbnailUrl == nil && in.CategoryId == nil
 }
 
 type Comment struct {
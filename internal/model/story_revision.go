@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// Diff operations of a DiffLine.
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// StoryRevision is a snapshot of a story as it was before an update replaced
+// it. Revision is the story version the snapshot holds. Tags are not
+// versioned: changing them bumps the story version, but revisions only hold
+// the story's own fields.
+type StoryRevision struct {
+	Id           int64      `json:"id"`
+	StoryId      int64      `json:"story_id"`
+	Revision     int64      `json:"revision"`
+	Title        string     `json:"title"`
+	Content      string     `json:"content"`
+	ThumbnailUrl string     `json:"thumbnail_url"`
+	CategoryId   int64      `json:"category_id"`
+	Diff         *StoryDiff `json:"diff,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// StoryDiff lists the line changes that turn a revision into the current
+// story. Unavailable is set instead when the texts are too long to diff.
+type StoryDiff struct {
+	Title       []DiffLine `json:"title"`
+	Content     []DiffLine `json:"content"`
+	Unavailable bool       `json:"unavailable,omitempty"`
+}
+
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
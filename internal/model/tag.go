@@ -0,0 +1,28 @@
+package model
+
+import "context"
+
+// MaxStoryTags is the number of distinct tags a story can have.
+const MaxStoryTags = 10
+
+type ITagUsecase interface {
+	FindAll(ctx context.Context) ([]*Tag, error)
+	FindBySlug(ctx context.Context, slug string) (*Tag, error)
+}
+
+type ITagRepository interface {
+	FindAll(ctx context.Context) ([]*Tag, error)
+	FindBySlug(ctx context.Context, slug string) (*Tag, error)
+	FindByStoryIDs(ctx context.Context, storyIds []int64) (map[int64][]*Tag, error)
+	Upsert(ctx context.Context, tags []Tag) ([]*Tag, error)
+	SetStoryTags(ctx context.Context, storyId int64, tagIds []int64) error
+}
+
+// Tag labels stories across categories. StoryCount is the number of published
+// stories with the tag and is only set by tag listings.
+type Tag struct {
+	Id         int64  `json:"id"`
+	Name       string `json:"name"`
+	Slug       string `json:"slug"`
+	StoryCount *int64 `json:"story_count,omitempty"`
+}
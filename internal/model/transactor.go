@@ -0,0 +1,9 @@
+package model
+
+import "context"
+
+// ITransactor runs a unit of work in a single database transaction. The
+// transaction is committed when fn returns nil and rolled back otherwise.
+type ITransactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+)
+
+// accountLookupConcurrency is the number of accounts looked up at once.
+const accountLookupConcurrency = 8
+
+// AccountRepo fetches accounts from the account service over HTTP.
+type AccountRepo struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewAccountRepo(baseURL string, timeout time.Duration) model.IAccountClient {
+	return &AccountRepo{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// FindByIDs looks up every account by ID, several at a time. Accounts the
+// service does not know about are left out of the result, and so are the
+// accounts whose lookup failed; those failures are returned joined together
+// alongside the accounts that were found.
+func (a *AccountRepo) FindByIDs(ctx context.Context, ids []int64) (map[int64]*model.Account, error) {
+	unique := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		unique[id] = true
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		errs     []error
+		accounts = make(map[int64]*model.Account, len(unique))
+		slots    = make(chan struct{}, accountLookupConcurrency)
+	)
+	for id := range unique {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(id int64) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			account, err := a.findByID(ctx, id)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("account %d: %w", id, err))
+				return
+			}
+			if account != nil {
+				accounts[id] = account
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	return accounts, errors.Join(errs...)
+}
+
+func (a *AccountRepo) findByID(ctx context.Context, id int64) (*model.Account, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/accounts/%d", a.baseURL, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("account service responded with status %d", res.StatusCode)
+	}
+
+	var body struct {
+		Data struct {
+			model.Account
+			Email string `json:"email"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	account := body.Data.Account
+	account.Email = body.Data.Email
+
+	return &account, nil
+}
@@ -3,7 +3,11 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kodinggo/gb-2-api-story-service/internal/helper"
 	"github.com/kodinggo/gb-2-api-story-service/internal/model"
 )
 
@@ -17,63 +21,355 @@ func NewCategoryRepo(db *sql.DB) model.ICategoryRepository {
 	}
 }
 
+// categoryColumns are the columns read by scanCategory.
+const categoryColumns = `id, name, slug, parent_id, description, icon_url, color, position, created_at, updated_at`
+
+func scanCategory(row rowScanner) (*model.Categories, error) {
+	var category model.Categories
+	var parentId sql.NullInt64
+	var position int64
+	if err := row.Scan(&category.Id, &category.Name, &category.Slug, &parentId, &category.Description, &category.IconUrl, &category.Color, &position, &category.CreatedAt, &category.UpdatedAt); err != nil {
+		return nil, err
+	}
+	category.Position = &position
+
+	if parentId.Valid {
+		category.ParentId = &parentId.Int64
+	}
+
+	return &category, nil
+}
+
 func (c *CategoryRepo) FindAll(ctx context.Context) ([]*model.Categories, error) {
-	res, err := c.db.QueryContext(ctx, `SELECT id, name, created_at, updated_at FROM categories`)
+	res, err := conn(ctx, c.db).QueryContext(ctx, `SELECT `+categoryColumns+` FROM categories WHERE deleted_at IS NULL ORDER BY position, id`)
 	if err != nil {
 		return nil, err
 	}
+	defer res.Close()
 
 	var categories []*model.Categories
 	for res.Next() {
-		var category model.Categories
-		if err := res.Scan(&category.Id, &category.Name, &category.CreatedAt, &category.UpdatedAt); err != nil {
+		category, err := scanCategory(res)
+		if err != nil {
 			return nil, err
 		}
-		categories = append(categories, &category)
+		categories = append(categories, category)
 	}
 
 	return categories, nil
 }
 
-func (c *CategoryRepo) FindById(ctx context.Context, id int64) (*model.Categories, error) {
-	res, err := c.db.QueryContext(ctx, `SELECT id, name, created_at, updated_at FROM categories WHERE id = ?`, id)
+// FindAllWithStats is FindAll with the cached story stats of each category.
+func (c *CategoryRepo) FindAllWithStats(ctx context.Context) ([]*model.Categories, error) {
+	res, err := conn(ctx, c.db).QueryContext(ctx, `SELECT `+categoryColumns+`, story_count, latest_story_at FROM categories WHERE deleted_at IS NULL ORDER BY position, id`)
 	if err != nil {
 		return nil, err
 	}
+	defer res.Close()
 
-	var category model.Categories
+	var categories []*model.Categories
 	for res.Next() {
-		if err := res.Scan(&category.Id, &category.Name, &category.CreatedAt, &category.UpdatedAt); err != nil {
+		var stats model.CategoryStats
+		var latestStoryAt sql.NullTime
+		category, err := scanCategory(rowScannerFunc(func(dest ...any) error {
+			return res.Scan(append(dest, &stats.StoryCount, &latestStoryAt)...)
+		}))
+		if err != nil {
 			return nil, err
 		}
+
+		if latestStoryAt.Valid {
+			stats.LatestStoryAt = &latestStoryAt.Time
+		}
+		category.Stats = &stats
+		categories = append(categories, category)
 	}
 
-	return &category, nil
+	return categories, nil
 }
 
-func (c *CategoryRepo) Create(ctx context.Context, category model.Categories) error {
-	_, err := c.db.ExecContext(ctx, `INSERT INTO categories (name) VALUES (?)`, category.Name)
+// RefreshStats recomputes the cached story stats of the given categories, or
+// of all categories when no IDs are given.
+func (c *CategoryRepo) RefreshStats(ctx context.Context, ids ...int64) error {
+	stats := sq.Select("category_id", "COUNT(*) AS story_count", "MAX(published_at) AS latest_story_at").
+		From("stories").
+		Where(sq.Eq{"status": model.StoryStatusPublished, "deleted_at": nil}).
+		GroupBy("category_id")
+	if len(ids) > 0 {
+		stats = stats.Where(sq.Eq{"category_id": ids})
+	}
+
+	statsQuery, args, err := stats.ToSql()
 	if err != nil {
 		return err
 	}
 
-	return nil
+	// updated_at is assigned to itself so refreshed stats do not count as an edit
+	query := `UPDATE categories AS c LEFT JOIN (` + statsQuery + `) AS s ON s.category_id = c.id
+		SET c.story_count = COALESCE(s.story_count, 0), c.latest_story_at = s.latest_story_at, c.updated_at = c.updated_at`
+	if len(ids) > 0 {
+		where, whereArgs, err := sq.Eq{"c.id": ids}.ToSql()
+		if err != nil {
+			return err
+		}
+		query += ` WHERE ` + where
+		args = append(args, whereArgs...)
+	}
+
+	_, err = conn(ctx, c.db).ExecContext(ctx, query, args...)
+	return err
 }
 
-func (c *CategoryRepo) Update(ctx context.Context, category model.Categories) error {
-	_, err := c.db.ExecContext(ctx, `UPDATE categories SET name = ? WHERE id = ?`, category.Name, category.Id)
+func (c *CategoryRepo) FindById(ctx context.Context, id int64) (*model.Categories, error) {
+	category, err := scanCategory(conn(ctx, c.db).QueryRowContext(ctx, `SELECT `+categoryColumns+` FROM categories WHERE id = ? AND deleted_at IS NULL`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.NewNotFoundError("category")
+	}
 	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// FindByIdForUpdate finds a category and locks it until the end of the
+// transaction in ctx, e.g. so no story is added to it while it is deleted.
+func (c *CategoryRepo) FindByIdForUpdate(ctx context.Context, id int64) (*model.Categories, error) {
+	return c.findByIdLocked(ctx, id, "FOR UPDATE")
+}
+
+// FindByIdForShare finds a category and keeps it from being changed or deleted
+// until the end of the transaction in ctx, e.g. while a story referencing it
+// is written.
+func (c *CategoryRepo) FindByIdForShare(ctx context.Context, id int64) (*model.Categories, error) {
+	return c.findByIdLocked(ctx, id, "LOCK IN SHARE MODE")
+}
+
+func (c *CategoryRepo) findByIdLocked(ctx context.Context, id int64, lock string) (*model.Categories, error) {
+	category, err := scanCategory(conn(ctx, c.db).QueryRowContext(ctx, `SELECT `+categoryColumns+` FROM categories WHERE id = ? AND deleted_at IS NULL `+lock, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.NewNotFoundError("category")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (c *CategoryRepo) FindBySlug(ctx context.Context, slug string) (*model.Categories, error) {
+	category, err := scanCategory(conn(ctx, c.db).QueryRowContext(ctx, `SELECT `+categoryColumns+` FROM categories WHERE slug = ? AND deleted_at IS NULL`, slug))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.NewNotFoundError("category")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// FindSlugRedirect returns the ID of the category that used to have the slug.
+func (c *CategoryRepo) FindSlugRedirect(ctx context.Context, slug string) (int64, error) {
+	return findSlugRedirect(ctx, c.db, slugResourceCategory, slug)
+}
+
+func (c *CategoryRepo) Create(ctx context.Context, category model.Categories) (*model.Categories, error) {
+	// New categories go last unless given a position
+	if category.Position == nil {
+		var next int64
+		err := conn(ctx, c.db).QueryRowContext(ctx, `SELECT COALESCE(MAX(position), 0) + 1 FROM categories WHERE deleted_at IS NULL`).Scan(&next)
+		if err != nil {
+			return nil, err
+		}
+		category.Position = &next
+	}
+
+	var res sql.Result
+	err := withUniqueSlug(ctx, conn(ctx, c.db), "categories", slugResourceCategory, helper.Slugify(category.Name), 0, func(slug string) error {
+		var err error
+		res, err = conn(ctx, c.db).ExecContext(ctx, `INSERT INTO categories (name, slug, parent_id, description, icon_url, color, position) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			category.Name, slug, category.ParentId, category.Description, category.IconUrl, category.Color, *category.Position)
 		return err
+	})
+	if isDuplicateKey(err, "categories_name_key") {
+		return nil, duplicateCategoryName(category.Name)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.FindById(ctx, id)
+}
+
+func (c *CategoryRepo) Update(ctx context.Context, category model.Categories) (*model.Categories, error) {
+	changes := map[string]any{
+		"name":        category.Name,
+		"parent_id":   category.ParentId,
+		"description": category.Description,
+		"icon_url":    category.IconUrl,
+		"color":       category.Color,
+	}
+	if category.Position != nil {
+		changes["position"] = *category.Position
+	}
+
+	return c.update(ctx, category.Id, changes)
+}
+
+// Patch writes only the columns present in the patch.
+func (c *CategoryRepo) Patch(ctx context.Context, id int64, in model.PatchCategoryInput) (*model.Categories, error) {
+	changes := map[string]any{}
+	if in.Name != nil {
+		changes["name"] = *in.Name
+	}
+	if in.Description != nil {
+		changes["description"] = *in.Description
+	}
+	if in.IconUrl != nil {
+		changes["icon_url"] = *in.IconUrl
+	}
+	if in.Color != nil {
+		changes["color"] = *in.Color
+	}
+	if in.Position != nil {
+		changes["position"] = *in.Position
+	}
+	if in.ParentId != nil {
+		if *in.ParentId == 0 {
+			changes["parent_id"] = nil
+		} else {
+			changes["parent_id"] = *in.ParentId
+		}
+	}
+
+	if len(changes) == 0 {
+		return c.FindById(ctx, id)
+	}
+
+	return c.update(ctx, id, changes)
 }
 
+// update applies changes. A new name also gives the category a new slug, with
+// the old one kept as a redirect.
+func (c *CategoryRepo) update(ctx context.Context, id int64, changes map[string]any) (*model.Categories, error) {
+	err := withTx(ctx, c.db, func(tx *sql.Tx) error {
+		var currentSlug string
+		err := tx.QueryRowContext(ctx, `SELECT slug FROM categories WHERE id = ? AND deleted_at IS NULL FOR UPDATE`, id).Scan(&currentSlug)
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.NewNotFoundError("category")
+		}
+		if err != nil {
+			return err
+		}
+
+		write := func() error {
+			query, args, err := sq.Update("categories").SetMap(changes).Where(sq.Eq{"id": id}).ToSql()
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.ExecContext(ctx, query, args...)
+			return err
+		}
+
+		if name, ok := changes["name"].(string); ok {
+			err = withUniqueSlug(ctx, tx, "categories", slugResourceCategory, helper.Slugify(name), id, func(slug string) error {
+				changes["slug"] = slug
+				return write()
+			})
+		} else {
+			err = write()
+		}
+		if name, ok := changes["name"].(string); ok && isDuplicateKey(err, "categories_name_key") {
+			return duplicateCategoryName(name)
+		}
+		if err != nil {
+			return err
+		}
+
+		if slug, ok := changes["slug"].(string); ok {
+			return recordSlugChange(ctx, tx, slugResourceCategory, id, currentSlug, slug)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.FindById(ctx, id)
+}
+
+// FindAncestorIDs returns the ID of the category followed by those of its
+// ancestors up to the top level, locking each of them until the end of the
+// transaction so the chain cannot change while a re-parent is checked.
+func (c *CategoryRepo) FindAncestorIDs(ctx context.Context, id int64) ([]int64, error) {
+	var ids []int64
+	seen := make(map[int64]bool)
+	for ancestor := id; !seen[ancestor]; {
+		var parentId sql.NullInt64
+		err := conn(ctx, c.db).QueryRowContext(ctx, `SELECT parent_id FROM categories WHERE id = ? AND deleted_at IS NULL FOR UPDATE`, ancestor).Scan(&parentId)
+		if errors.Is(err, sql.ErrNoRows) {
+			if ancestor == id {
+				return nil, model.NewNotFoundError("category")
+			}
+			// A parent that was deleted leaves the rest of the chain detached
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, ancestor)
+		seen[ancestor] = true
+		if !parentId.Valid {
+			break
+		}
+		ancestor = parentId.Int64
+	}
+
+	return ids, nil
+}
+
+// UpdatePosition sets the display position of a category.
+func (c *CategoryRepo) UpdatePosition(ctx context.Context, id int64, position int64) error {
+	_, err := conn(ctx, c.db).ExecContext(ctx, `UPDATE categories SET position = ? WHERE id = ? AND deleted_at IS NULL`, position, id)
+	return err
+}
+
+// MoveChildren gives the children of one category another parent, or makes
+// them top level categories when toParentId is nil.
+func (c *CategoryRepo) MoveChildren(ctx context.Context, fromParentId int64, toParentId *int64) error {
+	_, err := conn(ctx, c.db).ExecContext(ctx, `UPDATE categories SET parent_id = ? WHERE parent_id = ? AND deleted_at IS NULL`, toParentId, fromParentId)
+	return err
+}
+
+// Delete soft deletes the category. Stories referencing it must be moved
+// elsewhere first.
 func (c *CategoryRepo) Delete(ctx context.Context, id int64) error {
-	_, err := c.db.ExecContext(ctx, `DELETE FROM categories WHERE id = ?`, id)
+	res, err := conn(ctx, c.db).ExecContext(ctx, `UPDATE categories SET deleted_at = NOW() WHERE id = ? AND deleted_at IS NULL`, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
 	if err != nil {
 		return err
 	}
 
+	if affected == 0 {
+		return model.NewNotFoundError("category")
+	}
+
 	return nil
 }
+
+func duplicateCategoryName(name string) error {
+	return model.NewConflictError(fmt.Sprintf("a category named %q already exists", name))
+}
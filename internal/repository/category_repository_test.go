@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+)
+
+func TestCategoryRepoFindByIdNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Soft deleted categories are filtered out by the query, so they come
+	// back as no rows just like missing ones
+	mock.ExpectQuery(regexp.QuoteMeta("FROM categories WHERE id = ? AND deleted_at IS NULL")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	category, err := NewCategoryRepo(db).FindById(context.Background(), 1)
+	if !errors.Is(err, model.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if category != nil {
+		t.Fatalf("expected no category, got %+v", category)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
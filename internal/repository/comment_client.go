@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kodinggo/gb-2-api-comment-service/pb/comment_service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CommentClientOptions tunes how CommentClient calls the comment service. Zero
+// values disable the matching behaviour.
+type CommentClientOptions struct {
+	// Timeout is the deadline of each attempt
+	Timeout time.Duration
+	// MaxRetries is the number of extra attempts after a transient failure
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry, doubled on every retry
+	RetryBackoff time.Duration
+	// BreakerThreshold is the number of consecutive failed calls that opens
+	// the circuit breaker
+	BreakerThreshold int
+	// BreakerCooldown is how long the breaker stays open before letting a
+	// single probe call through
+	BreakerCooldown time.Duration
+}
+
+// errBreakerOpen is returned without calling the comment service while the
+// circuit breaker is open.
+var errBreakerOpen = status.Error(codes.Unavailable, "comment service circuit breaker is open")
+
+// CommentClient wraps a comment service client with per-attempt deadlines,
+// retries of transient failures and a circuit breaker, so an unhealthy
+// comment service fails fast instead of slowing down every story read.
+type CommentClient struct {
+	client comment_service.CommentServiceClient
+	opts   CommentClientOptions
+
+	mu        sync.Mutex
+	failures  int
+	open      bool
+	openUntil time.Time
+	probing   bool
+}
+
+func NewCommentClient(client comment_service.CommentServiceClient, opts CommentClientOptions) comment_service.CommentServiceClient {
+	return &CommentClient{
+		client: client,
+		opts:   opts,
+	}
+}
+
+func (c *CommentClient) FindAllByStoryID(ctx context.Context, in *comment_service.FindAllByStoryIDRequest, opts ...grpc.CallOption) (*comment_service.Comments, error) {
+	return c.call(ctx, func(ctx context.Context) (*comment_service.Comments, error) {
+		return c.client.FindAllByStoryID(ctx, in, opts...)
+	})
+}
+
+func (c *CommentClient) FindAllByStoryIDs(ctx context.Context, in *comment_service.FindAllByStoryIDsRequest, opts ...grpc.CallOption) (*comment_service.Comments, error) {
+	return c.call(ctx, func(ctx context.Context) (*comment_service.Comments, error) {
+		return c.client.FindAllByStoryIDs(ctx, in, opts...)
+	})
+}
+
+// call runs fn through the circuit breaker, retrying transient failures with
+// exponential backoff.
+func (c *CommentClient) call(ctx context.Context, fn func(ctx context.Context) (*comment_service.Comments, error)) (*comment_service.Comments, error) {
+	allowed, probe := c.allow()
+	if !allowed {
+		return nil, errBreakerOpen
+	}
+
+	// A caller that gave up, during an attempt or the backoff before the
+	// next one, says nothing about the service's health, so the breaker is
+	// left as it was, releasing a probe for the next call
+	var res *comment_service.Comments
+	var err error
+	backoff := c.opts.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		res, err = c.attempt(ctx, fn)
+		if err != nil && ctx.Err() != nil {
+			c.release(probe)
+			return nil, err
+		}
+		if err == nil || !isTransient(err) || attempt >= c.opts.MaxRetries {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			c.release(probe)
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	c.record(err, probe)
+	return res, err
+}
+
+func (c *CommentClient) attempt(ctx context.Context, fn func(ctx context.Context) (*comment_service.Comments, error)) (*comment_service.Comments, error) {
+	if c.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opts.Timeout)
+		defer cancel()
+	}
+
+	return fn(ctx)
+}
+
+// allow reports whether a call may go through. While the breaker is open,
+// calls are refused until the cooldown has passed. Then a single call is let
+// through as a probe, and other calls keep being refused until its result
+// either closes the breaker or opens it for another cooldown.
+func (c *CommentClient) allow() (allowed bool, probe bool) {
+	if c.opts.BreakerThreshold <= 0 {
+		return true, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.open {
+		return true, false
+	}
+
+	if c.probing || time.Now().Before(c.openUntil) {
+		return false, false
+	}
+
+	c.probing = true
+	return true, true
+}
+
+// release lets another probe through after a probe ended without an outcome.
+func (c *CommentClient) release(probe bool) {
+	if !probe || c.opts.BreakerThreshold <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.probing = false
+}
+
+// record updates the breaker with the outcome of a call. Only transient
+// failures count, since other errors say nothing about the service's health.
+func (c *CommentClient) record(err error, probe bool) {
+	if c.opts.BreakerThreshold <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if probe {
+		c.probing = false
+	}
+
+	// A call its caller gave up on leaves the breaker as it was, so an
+	// abandoned probe is retried by the next call
+	if status.Code(err) == codes.Canceled {
+		return
+	}
+
+	if err == nil || !isTransient(err) {
+		c.failures = 0
+		c.open = false
+		return
+	}
+
+	c.failures++
+	if probe || c.failures >= c.opts.BreakerThreshold {
+		c.open = true
+		c.openUntil = time.Now().Add(c.opts.BreakerCooldown)
+	}
+}
+
+// isTransient reports whether a failed call may succeed when retried.
+func isTransient(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kodinggo/gb-2-api-comment-service/pb/comment_service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// unavailableComments fails every call as if the comment service were down.
+type unavailableComments struct {
+	comment_service.CommentServiceClient
+}
+
+func (unavailableComments) FindAllByStoryID(context.Context, *comment_service.FindAllByStoryIDRequest, ...grpc.CallOption) (*comment_service.Comments, error) {
+	return nil, status.Error(codes.Unavailable, "comment service is down")
+}
+
+func TestCommentClientCallerGivesUp(t *testing.T) {
+	// The backoff outlasts the caller, which gives up after the first attempt
+	client := NewCommentClient(unavailableComments{}, CommentClientOptions{
+		MaxRetries:       1,
+		RetryBackoff:     time.Hour,
+		BreakerThreshold: 1,
+		BreakerCooldown:  time.Minute,
+	}).(*CommentClient)
+
+	call := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := client.FindAllByStoryID(ctx, &comment_service.FindAllByStoryIDRequest{StoryId: 1})
+		return err
+	}
+
+	if err := call(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the caller's deadline, got %v", err)
+	}
+	if client.open || client.failures != 0 {
+		t.Fatalf("expected the breaker to stay closed, got open %v after %d failures", client.open, client.failures)
+	}
+
+	// A half-open probe abandoned by its caller lets the next call probe again
+	openUntil := time.Now().Add(-time.Second)
+	client.open, client.openUntil = true, openUntil
+	if err := call(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the caller's deadline, got %v", err)
+	}
+	if client.probing || !client.openUntil.Equal(openUntil) {
+		t.Fatalf("expected the probe to be released without a new cooldown, got probing %v until %v", client.probing, client.openUntil)
+	}
+	if allowed, probe := client.allow(); !allowed || !probe {
+		t.Fatalf("expected the next call to probe, got allowed %v probe %v", allowed, probe)
+	}
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// MySQL error numbers handled by the repositories.
+const (
+	// mysqlDuplicateEntry is the error number of unique index violations
+	mysqlDuplicateEntry = 1062
+	// mysqlDeadlock is the error number of a transaction rolled back to
+	// break a deadlock
+	mysqlDeadlock = 1213
+)
+
+// isDuplicateKey reports whether err is a violation of the named unique index.
+func isDuplicateKey(err error, index string) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry && strings.Contains(mysqlErr.Message, index)
+}
+
+// isDeadlock reports whether err rolled the transaction back to break a
+// deadlock.
+func isDeadlock(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDeadlock
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+)
+
+// Resources whose old slugs are kept in slug_redirects.
+const (
+	slugResourceStory    = "story"
+	slugResourceCategory = "category"
+)
+
+// slugAttempts bounds how often a write is retried after losing the race for
+// its slug to a concurrent write.
+const slugAttempts = 3
+
+// withUniqueSlug picks a slug for base with uniqueSlug and runs write with it.
+// Concurrent writes can pick the same free slug; the one that loses hits the
+// table's unique slug index and is retried with the next free slug.
+func withUniqueSlug(ctx context.Context, exec executor, table string, resource string, base string, id int64, write func(slug string) error) error {
+	var lost []string
+	for {
+		slug, err := uniqueSlug(ctx, exec, table, resource, base, id, lost...)
+		if err != nil {
+			return err
+		}
+
+		err = write(slug)
+		if !isDuplicateKey(err, table+"_slug") {
+			return err
+		}
+
+		lost = append(lost, slug)
+		if len(lost) >= slugAttempts {
+			return model.NewConflictError(fmt.Sprintf("no free slug for %q, please retry", base))
+		}
+	}
+}
+
+// uniqueSlug returns base, or base with the lowest numeric suffix, that no
+// row of table other than id uses as its current or an old slug, and that is
+// not one of taken. An empty base falls back to the resource name.
+func uniqueSlug(ctx context.Context, exec executor, table string, resource string, base string, id int64, taken ...string) (string, error) {
+	if base == "" {
+		base = resource
+	}
+
+	query := fmt.Sprintf(`SELECT slug FROM %s WHERE (slug = ? OR slug LIKE ?) AND id <> ?
+		UNION SELECT slug FROM slug_redirects WHERE resource = ? AND (slug = ? OR slug LIKE ?) AND target_id <> ?`, table)
+
+	// Slugs only contain [a-z0-9-], so base needs no escaping in LIKE
+	res, err := exec.QueryContext(ctx, query, base, base+"-%", id, resource, base, base+"-%", id)
+	if err != nil {
+		return "", err
+	}
+	defer res.Close()
+
+	used := make(map[string]bool, len(taken))
+	for _, slug := range taken {
+		used[slug] = true
+	}
+	for res.Next() {
+		var slug string
+		if err := res.Scan(&slug); err != nil {
+			return "", err
+		}
+		used[slug] = true
+	}
+	if err := res.Err(); err != nil {
+		return "", err
+	}
+
+	slug := base
+	for n := 2; used[slug]; n++ {
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+
+	return slug, nil
+}
+
+// recordSlugChange keeps oldSlug as a redirect to id. A redirect that newSlug
+// takes back over is dropped, since uniqueSlug only hands out old slugs of
+// the same row.
+func recordSlugChange(ctx context.Context, tx *sql.Tx, resource string, id int64, oldSlug string, newSlug string) error {
+	if oldSlug == newSlug || oldSlug == "" {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `DELETE FROM slug_redirects WHERE resource = ? AND slug = ?`, resource, newSlug)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO slug_redirects (resource, slug, target_id) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE target_id = VALUES(target_id)`, resource, oldSlug, id)
+	return err
+}
+
+// findSlugRedirect returns the ID an old slug now points to.
+func findSlugRedirect(ctx context.Context, db *sql.DB, resource string, slug string) (int64, error) {
+	var id int64
+	err := conn(ctx, db).QueryRowContext(ctx, `SELECT target_id FROM slug_redirects WHERE resource = ? AND slug = ?`, resource, slug).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, model.NewNotFoundError(resource)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
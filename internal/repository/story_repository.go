@@ -3,8 +3,13 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"sort"
+	"strings"
 	"time"
 
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kodinggo/gb-2-api-story-service/internal/helper"
 	"github.com/kodinggo/gb-2-api-story-service/internal/model"
 )
 
@@ -18,126 +23,714 @@ func NewStoryRepo(db *sql.DB) model.IStoryRepository {
 	}
 }
 
+// storyOrderBy maps the whitelisted sort parameter to its ORDER BY clause.
+var storyOrderBy = map[string][]string{
+	model.SortNewest:        {"s.created_at DESC", "s.id DESC"},
+	model.SortOldest:        {"s.created_at ASC", "s.id ASC"},
+	model.SortTitle:         {"s.title ASC", "s.id ASC"},
+	model.SortUpdated:       {"s.updated_at DESC", "s.id DESC"},
+	model.SortMostCommented: {"s.comment_count DESC", "s.created_at DESC", "s.id DESC"},
+}
+
+// selectStories selects the columns read by scanStory.
+func selectStories() sq.SelectBuilder {
+	return sq.Select("s.id", "s.title", "s.slug", "s.content", "s.thumbnail_url", "c.id AS category_id", "c.name AS category_name", "c.slug AS category_slug", "s.user_id", "s.status", "s.published_at", "s.comment_count", "s.version", "s.created_at", "s.updated_at", "s.deleted_at").
+		From("stories AS s").
+		LeftJoin("categories AS c ON s.category_id = c.id")
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// rowScannerFunc adapts a function to rowScanner, e.g. to scan extra columns
+// after those read by a shared scan helper.
+type rowScannerFunc func(dest ...any) error
+
+func (f rowScannerFunc) Scan(dest ...any) error {
+	return f(dest...)
+}
+
+// scanStory scans a row selected by selectStories.
+func scanStory(row rowScanner) (*model.Story, error) {
+	var story model.Story
+	var categoryId sql.NullInt64
+	var categoryName sql.NullString
+	var categorySlug sql.NullString
+	var publishedAt sql.NullTime
+	var deletedAt sql.NullTime
+
+	if err := row.Scan(&story.Id, &story.Title, &story.Slug, &story.Content, &story.ThumbnailUrl, &categoryId, &categoryName, &categorySlug, &story.Author.Id, &story.Status, &publishedAt, &story.CommentCount, &story.Version, &story.CreatedAt, &story.UpdatedAt, &deletedAt); err != nil {
+		return nil, err
+	}
+
+	if categoryId.Valid && categoryName.Valid {
+		story.Category = model.Category{
+			Id:   categoryId.Int64,
+			Name: categoryName.String,
+			Slug: categorySlug.String,
+		}
+	}
+
+	if publishedAt.Valid {
+		story.PublishedAt = &publishedAt.Time
+	}
+
+	if deletedAt.Valid {
+		story.DeletedAt = &deletedAt.Time
+	}
+
+	return &story, nil
+}
+
 func (s *StoryRepo) FindAll(ctx context.Context, filter model.FindAllParam) ([]*model.Story, error) {
-	query := `SELECT s.id, s.title, s.content, s.thumbnail_url, c.id AS category_id, c.name AS category_name, s.created_at, s.updated_at FROM stories AS s LEFT JOIN stories AS sc ON s.id = sc.id LEFT JOIN categories AS c ON sc.category_id = c.id WHERE s.deleted_at IS NULL ORDER BY s.created_at DESC LIMIT ? OFFSET ?`
+	builder := applyStoryFilter(selectStories(), filter)
+
+	// Keyset pagination walks (created_at, id) from the cursor in the requested
+	// direction. Backward pages are read in reverse and flipped afterwards.
+	reverse := false
+	if filter.Cursor != nil {
+		ascending := filter.Sort == model.SortOldest
+		if filter.Cursor.Backward {
+			ascending = !ascending
+			reverse = true
+		}
+
+		if ascending {
+			builder = builder.Where("(s.created_at, s.id) > (?, ?)", filter.Cursor.CreatedAt, filter.Cursor.Id).
+				OrderBy(storyOrderBy[model.SortOldest]...)
+		} else {
+			builder = builder.Where("(s.created_at, s.id) < (?, ?)", filter.Cursor.CreatedAt, filter.Cursor.Id).
+				OrderBy(storyOrderBy[model.SortNewest]...)
+		}
+	} else if filter.Query != "" && filter.Sort == "" {
+		// Rank by full-text relevance when searching without an explicit sort
+		builder = builder.OrderByClause("MATCH(s.title, s.content) AGAINST (? IN NATURAL LANGUAGE MODE) DESC", filter.Query).
+			OrderBy(storyOrderBy[model.SortNewest]...)
+	} else if orderBy, ok := storyOrderBy[filter.Sort]; ok {
+		builder = builder.OrderBy(orderBy...)
+	} else {
+		builder = builder.OrderBy(storyOrderBy[model.SortNewest]...)
+	}
+
+	builder = builder.Limit(uint64(filter.Limit))
+	if filter.Cursor == nil && filter.Offset > 0 {
+		builder = builder.Offset(uint64(filter.Offset))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
 
 	// Execute query
-	res, err := s.db.QueryContext(ctx, query, filter.Limit, filter.Page)
+	res, err := conn(ctx, s.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Close()
 
-	storyMap := make(map[int64]*model.Story)
+	var stories []*model.Story
 	for res.Next() {
-		var storyId int64
-		var title, content, thumbnailUrl, categoryName string
-		var categoryId sql.NullInt64
-		var createdAt, updatedAt time.Time
-
-		if err := res.Scan(&storyId, &title, &content, &thumbnailUrl, &categoryId, &categoryName, &createdAt, &updatedAt); err != nil {
+		story, err := scanStory(res)
+		if err != nil {
 			return nil, err
 		}
+		stories = append(stories, story)
+	}
 
-		if story, exists := storyMap[storyId]; exists {
-			if categoryId.Valid && story.Category.Id == 0 {
-				story.Category = model.Category{
-					Id:   categoryId.Int64,
-					Name: categoryName,
-				}
-			}
-		} else {
-			newStory := &model.Story{
-				Id:           storyId,
-				Title:        title,
-				Content:      content,
-				ThumbnailUrl: thumbnailUrl,
-				CreatedAt:    createdAt,
-				UpdatedAt:    updatedAt,
-			}
+	if reverse {
+		for i, j := 0, len(stories)-1; i < j; i, j = i+1, j-1 {
+			stories[i], stories[j] = stories[j], stories[i]
+		}
+	}
 
-			if categoryId.Valid {
-				newStory.Category = model.Category{
-					Id:   categoryId.Int64,
-					Name: categoryName,
-				}
-			}
+	return stories, nil
+}
 
-			storyMap[storyId] = newStory
-		}
+func (s *StoryRepo) Count(ctx context.Context, filter model.FindAllParam) (int64, error) {
+	query, args, err := applyStoryFilter(sq.Select("COUNT(*)").From("stories AS s"), filter).ToSql()
+	if err != nil {
+		return 0, err
 	}
 
-	var stories []*model.Story
-	for _, story := range storyMap {
-		stories = append(stories, story)
+	var total int64
+	if err := conn(ctx, s.db).QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return 0, err
 	}
-	return stories, nil
 
+	return total, nil
+}
+
+// applyStoryFilter adds the listing filters shared by FindAll and Count.
+func applyStoryFilter(builder sq.SelectBuilder, filter model.FindAllParam) sq.SelectBuilder {
+	builder = builder.Where(sq.Eq{"s.deleted_at": nil})
+
+	if filter.Status != "" {
+		builder = builder.Where(sq.Eq{"s.status": filter.Status})
+	}
+
+	if filter.Query != "" {
+		builder = builder.Where("MATCH(s.title, s.content) AGAINST (? IN NATURAL LANGUAGE MODE)", filter.Query)
+	}
+
+	if len(filter.CategoryIDs) > 0 {
+		builder = builder.Where(sq.Eq{"s.category_id": filter.CategoryIDs})
+	}
+
+	if filter.UserID > 0 {
+		builder = builder.Where(sq.Eq{"s.user_id": filter.UserID})
+	}
+
+	if filter.Tag != "" {
+		builder = builder.Where("s.id IN (SELECT st.story_id FROM story_tags AS st JOIN tags AS t ON t.id = st.tag_id WHERE t.slug = ?)", filter.Tag)
+	}
+
+	if filter.CreatedFrom != nil {
+		builder = builder.Where(sq.GtOrEq{"s.created_at": *filter.CreatedFrom})
+	}
+
+	if filter.CreatedTo != nil {
+		builder = builder.Where(sq.LtOrEq{"s.created_at": *filter.CreatedTo})
+	}
+
+	return builder
 }
 
 func (s *StoryRepo) FindById(ctx context.Context, id int64) (*model.Story, error) {
-	query := `SELECT s.id, s.title, s.content, s.thumbnail_url, c.id AS category_id, c.name AS category_name, s.created_at, s.updated_at, s.deleted_at FROM stories AS s LEFT JOIN stories AS sc ON s.id = sc.id LEFT JOIN categories AS c ON sc.category_id = c.id WHERE s.id = ? LIMIT 1`
+	query, args, err := selectStories().Where(sq.Eq{"s.id": id, "s.deleted_at": nil}).Limit(1).ToSql()
+	if err != nil {
+		return nil, err
+	}
 
 	// Execute query to fetch one story by id
-	res, err := s.db.QueryContext(ctx, query, id)
+	story, err := scanStory(conn(ctx, s.db).QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.NewNotFoundError("story")
+	}
 	if err != nil {
 		return nil, err
 	}
-	defer res.Close()
 
-	var story model.Story
+	return story, nil
+}
 
-	if res.Next() {
-		var categoryId sql.NullInt64
-		var categoryName sql.NullString
-		var createdAt, updatedAt time.Time
-		var deletedAt sql.NullTime
+func (s *StoryRepo) FindBySlug(ctx context.Context, slug string) (*model.Story, error) {
+	query, args, err := selectStories().Where(sq.Eq{"s.slug": slug, "s.deleted_at": nil}).Limit(1).ToSql()
+	if err != nil {
+		return nil, err
+	}
 
-		if err := res.Scan(&story.Id, &story.Title, &story.Content, &story.ThumbnailUrl, &categoryId, &categoryName, &createdAt, &updatedAt, &deletedAt); err != nil {
+	story, err := scanStory(conn(ctx, s.db).QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.NewNotFoundError("story")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return story, nil
+}
+
+// FindSlugRedirect returns the ID of the story that used to have the slug.
+func (s *StoryRepo) FindSlugRedirect(ctx context.Context, slug string) (int64, error) {
+	return findSlugRedirect(ctx, s.db, slugResourceStory, slug)
+}
+
+func (s *StoryRepo) FindByIDs(ctx context.Context, ids []int64) ([]*model.Story, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := selectStories().Where(sq.Eq{"s.id": ids, "s.deleted_at": nil}).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	// Execute query to fetch stories by ids
+	res, err := conn(ctx, s.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	var stories []*model.Story
+	for res.Next() {
+		story, err := scanStory(res)
+		if err != nil {
 			return nil, err
 		}
+		stories = append(stories, story)
+	}
 
-		if categoryId.Valid && categoryName.Valid {
-			story.Category = model.Category{
-				Id:   categoryId.Int64,
-				Name: categoryName.String,
+	return stories, nil
+}
+
+func (s *StoryRepo) Create(ctx context.Context, story model.Story) (*model.Story, error) {
+	var res sql.Result
+	err := withUniqueSlug(ctx, conn(ctx, s.db), "stories", slugResourceStory, helper.Slugify(story.Title), 0, func(slug string) error {
+		var err error
+		res, err = conn(ctx, s.db).ExecContext(ctx, `INSERT INTO stories (title, slug, content, thumbnail_url, category_id, user_id, status, published_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, story.Title, slug, story.Content, story.ThumbnailUrl, story.Category.Id, story.Author.Id, story.Status, story.PublishedAt)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.FindById(ctx, id)
+}
+
+// Update overwrites the story. When story.Version is set, the update only
+// applies if the stored version still matches it.
+func (s *StoryRepo) Update(ctx context.Context, story model.Story) (*model.Story, error) {
+	return s.update(ctx, story.Id, story.Version, map[string]any{
+		"title":         story.Title,
+		"content":       story.Content,
+		"thumbnail_url": story.ThumbnailUrl,
+		"category_id":   story.Category.Id,
+	})
+}
+
+// Patch writes only the columns present in the patch, under the same version
+// check as Update.
+func (s *StoryRepo) Patch(ctx context.Context, id int64, version int64, in model.PatchStoryInput) (*model.Story, error) {
+	changes := map[string]any{}
+	if in.Title != nil {
+		changes["title"] = *in.Title
+	}
+	if in.Content != nil {
+		changes["content"] = *in.Content
+	}
+	if in.ThumbnailUrl != nil {
+		changes["thumbnail_url"] = *in.ThumbnailUrl
+	}
+	if in.CategoryId != nil {
+		changes["category_id"] = *in.CategoryId
+	}
+
+	if len(changes) == 0 {
+		return s.FindById(ctx, id)
+	}
+
+	return s.update(ctx, id, version, changes)
+}
+
+// Touch bumps the version of a story whose tags changed, under the same
+// version check as Update, so conditional writes also cover tag edits.
+func (s *StoryRepo) Touch(ctx context.Context, id int64, version int64) (*model.Story, error) {
+	return s.update(ctx, id, version, map[string]any{})
+}
+
+// update applies changes and records the state they replace as a revision,
+// both in one transaction. A new title also gives the story a new slug, with
+// the old one kept as a redirect.
+func (s *StoryRepo) update(ctx context.Context, id int64, version int64, changes map[string]any) (*model.Story, error) {
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		// Lock the row so the revision matches the version being replaced
+		var current int64
+		var currentSlug string
+		err := tx.QueryRowContext(ctx, `SELECT version, slug FROM stories WHERE id = ? AND deleted_at IS NULL FOR UPDATE`, id).Scan(&current, &currentSlug)
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.NewNotFoundError("story")
+		}
+		if err != nil {
+			return err
+		}
+
+		if version > 0 && current != version {
+			return model.ErrPreconditionFailed
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO story_revisions (story_id, revision, title, content, thumbnail_url, category_id, created_at)
+			SELECT id, version, title, content, thumbnail_url, category_id, updated_at FROM stories WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+
+		write := func() error {
+			query, args, err := sq.Update("stories").SetMap(changes).Set("version", sq.Expr("version + 1")).Where(sq.Eq{"id": id}).ToSql()
+			if err != nil {
+				return err
 			}
+
+			_, err = tx.ExecContext(ctx, query, args...)
+			return err
 		}
 
-		story.CreatedAt = createdAt
-		story.UpdatedAt = updatedAt
-		story.DeletedAt = deletedAt
+		if title, ok := changes["title"].(string); ok {
+			err = withUniqueSlug(ctx, tx, "stories", slugResourceStory, helper.Slugify(title), id, func(slug string) error {
+				changes["slug"] = slug
+				return write()
+			})
+		} else {
+			err = write()
+		}
+		if err != nil {
+			return err
+		}
+
+		if slug, ok := changes["slug"].(string); ok {
+			return recordSlugChange(ctx, tx, slugResourceStory, id, currentSlug, slug)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &story, nil
+	return s.FindById(ctx, id)
+}
+
+// checkAffected explains why a conditional write touched no row: either the
+// story is gone or its version moved on.
+func (s *StoryRepo) checkAffected(ctx context.Context, res sql.Result, id int64) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
 
+	if affected > 0 {
+		return nil
+	}
+
+	if _, err := s.FindById(ctx, id); err != nil {
+		return err
+	}
+
+	return model.ErrPreconditionFailed
 }
 
-func (s *StoryRepo) Create(ctx context.Context, story model.Story) error {
-	_, err := s.db.ExecContext(ctx, `INSERT INTO stories (title, content, thumbnail_url, category_id) VALUES (?, ?, ?, ?)`, story.Title, story.Content, story.ThumbnailUrl, story.Category.Id)
+// Delete soft deletes the story, under the same version check as Update.
+func (s *StoryRepo) Delete(ctx context.Context, id int64, version int64) error {
+	where := sq.Eq{"id": id, "deleted_at": nil}
+	if version > 0 {
+		where["version"] = version
+	}
+
+	query, args, err := sq.Update("stories").Set("deleted_at", time.Now()).Where(where).ToSql()
 	if err != nil {
 		return err
 	}
 
-	return nil
+	res, err := conn(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return s.checkAffected(ctx, res, id)
 }
 
-func (s *StoryRepo) Update(ctx context.Context, story model.Story) error {
-	_, err := s.db.ExecContext(ctx, `UPDATE stories SET title = ?, content = ?, thumbnail_url = ?, category_id = ? WHERE id = ?`, story.Title, story.Content, story.ThumbnailUrl, story.Category.Id, story.Id)
+// UpdateStatus moves the story to another lifecycle status under the same
+// version check as Update. Status changes do not record a revision.
+func (s *StoryRepo) UpdateStatus(ctx context.Context, id int64, version int64, status string, publishedAt *time.Time) (*model.Story, error) {
+	where := sq.Eq{"id": id, "deleted_at": nil}
+	if version > 0 {
+		where["version"] = version
+	}
+
+	query, args, err := sq.Update("stories").
+		Set("status", status).
+		Set("published_at", publishedAt).
+		Set("version", sq.Expr("version + 1")).
+		Where(where).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := conn(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkAffected(ctx, res, id); err != nil {
+		return nil, err
+	}
+
+	return s.FindById(ctx, id)
+}
+
+// PublishDue publishes scheduled stories whose publication time is not after
+// now and returns how many were published.
+func (s *StoryRepo) PublishDue(ctx context.Context, now time.Time) (int64, error) {
+	res, err := conn(ctx, s.db).ExecContext(ctx, `UPDATE stories SET status = ?, version = version + 1 WHERE status = ? AND published_at <= ? AND deleted_at IS NULL`, model.StoryStatusPublished, model.StoryStatusScheduled, now)
 	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// FindTrash lists soft deleted stories, most recently deleted first.
+func (s *StoryRepo) FindTrash(ctx context.Context, limit int64, offset int64) ([]*model.Story, error) {
+	query, args, err := selectStories().
+		Where(sq.NotEq{"s.deleted_at": nil}).
+		OrderBy("s.deleted_at DESC", "s.id DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := conn(ctx, s.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	var stories []*model.Story
+	for res.Next() {
+		story, err := scanStory(res)
+		if err != nil {
+			return nil, err
+		}
+		stories = append(stories, story)
+	}
+
+	return stories, nil
+}
+
+// Restore brings a soft deleted story back, moving it to categoryId unless
+// that is 0. A story whose category has since been deleted must be moved.
+func (s *StoryRepo) Restore(ctx context.Context, id int64, categoryId int64) (*model.Story, error) {
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		var currentCategoryId int64
+		var categoryDeleted bool
+		err := tx.QueryRowContext(ctx, `SELECT s.category_id, c.deleted_at IS NOT NULL FROM stories AS s JOIN categories AS c ON c.id = s.category_id
+			WHERE s.id = ? AND s.deleted_at IS NOT NULL FOR UPDATE`, id).Scan(&currentCategoryId, &categoryDeleted)
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.NewNotFoundError("deleted story")
+		}
+		if err != nil {
+			return err
+		}
+
+		if categoryId == 0 {
+			if categoryDeleted {
+				return model.NewConflictError("the category of the story was deleted, restore it into another category with category_id")
+			}
+			categoryId = currentCategoryId
+		} else if categoryId != currentCategoryId || categoryDeleted {
+			// Lock the target so it cannot be deleted before the story is restored
+			var exists int
+			err := tx.QueryRowContext(ctx, `SELECT 1 FROM categories WHERE id = ? AND deleted_at IS NULL LOCK IN SHARE MODE`, categoryId).Scan(&exists)
+			if errors.Is(err, sql.ErrNoRows) {
+				return model.NewUnprocessableError("category_id", "refers to a category that does not exist")
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE stories SET deleted_at = NULL, category_id = ?, version = version + 1 WHERE id = ?`, categoryId, id)
 		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return s.FindById(ctx, id)
 }
 
-func (s *StoryRepo) Delete(ctx context.Context, id int64) error {
-	currentTime := time.Now()
+// Purge permanently deletes a soft deleted story along with its revisions and
+// tags.
+func (s *StoryRepo) Purge(ctx context.Context, id int64) error {
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `DELETE FROM story_revisions WHERE story_id = ?`, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE r FROM slug_redirects AS r JOIN stories AS s ON s.id = r.target_id WHERE r.resource = ? AND s.id = ? AND s.deleted_at IS NOT NULL`, slugResourceStory, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE t FROM story_tags AS t JOIN stories AS s ON s.id = t.story_id WHERE s.id = ? AND s.deleted_at IS NOT NULL`, id)
+		if err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx, `DELETE FROM stories WHERE id = ? AND deleted_at IS NOT NULL`, id)
+		if err != nil {
+			return err
+		}
 
-	_, err := s.db.ExecContext(ctx, `UPDATE stories SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`, currentTime, id)
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return model.NewNotFoundError("deleted story")
+		}
+
+		return nil
+	})
+}
+
+// PurgeDeletedBefore permanently deletes the stories soft deleted before the
+// given time, along with their revisions and tags, and returns how many were
+// deleted.
+func (s *StoryRepo) PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `DELETE r FROM story_revisions AS r JOIN stories AS s ON s.id = r.story_id WHERE s.deleted_at < ?`, before)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE r FROM slug_redirects AS r JOIN stories AS s ON s.id = r.target_id WHERE r.resource = ? AND s.deleted_at < ?`, slugResourceStory, before)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE t FROM story_tags AS t JOIN stories AS s ON s.id = t.story_id WHERE s.deleted_at < ?`, before)
+		if err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx, `DELETE FROM stories WHERE deleted_at < ?`, before)
+		if err != nil {
+			return err
+		}
+
+		purged, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, nil
+}
+
+// CountByCategory counts the stories, excluding deleted ones, that belong to
+// the category.
+func (s *StoryRepo) CountByCategory(ctx context.Context, categoryId int64) (int64, error) {
+	var total int64
+	err := conn(ctx, s.db).QueryRowContext(ctx, `SELECT COUNT(*) FROM stories WHERE category_id = ? AND deleted_at IS NULL`, categoryId).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// ReassignCategory moves every story of one category, deleted ones included,
+// to another and returns how many were moved.
+func (s *StoryRepo) ReassignCategory(ctx context.Context, fromCategoryId int64, toCategoryId int64) (int64, error) {
+	res, err := conn(ctx, s.db).ExecContext(ctx, `UPDATE stories SET category_id = ?, version = version + 1 WHERE category_id = ?`, toCategoryId, fromCategoryId)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// FindCommentCounts returns the stored comment counts of up to limit stories,
+// excluding deleted ones, with IDs above afterId, keyed by story ID. The
+// highest ID returned is the afterId of the next batch, or 0 after the last.
+func (s *StoryRepo) FindCommentCounts(ctx context.Context, afterId int64, limit int64) (map[int64]int64, int64, error) {
+	res, err := conn(ctx, s.db).QueryContext(ctx, `SELECT id, comment_count FROM stories WHERE id > ? AND deleted_at IS NULL ORDER BY id LIMIT ?`, afterId, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer res.Close()
+
+	counts := make(map[int64]int64)
+	var lastId int64
+	for res.Next() {
+		var id, count int64
+		if err := res.Scan(&id, &count); err != nil {
+			return nil, 0, err
+		}
+		counts[id] = count
+		lastId = id
+	}
+	if err := res.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return counts, lastId, nil
+}
+
+// UpdateCommentCounts stores the comment counts of stories, keyed by story ID.
+func (s *StoryRepo) UpdateCommentCounts(ctx context.Context, counts map[int64]int64) error {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(counts))
+	for id := range counts {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	// All counts are written by a single statement, mapping each ID to its count
+	var cases strings.Builder
+	args := make([]any, 0, len(ids)*2)
+	for _, id := range ids {
+		cases.WriteString(" WHEN ? THEN ?")
+		args = append(args, id, counts[id])
+	}
+
+	where, whereArgs, err := sq.Eq{"id": ids}.ToSql()
 	if err != nil {
 		return err
 	}
 
-	return nil
+	// updated_at is assigned to itself so a refreshed count does not count as an edit
+	query := `UPDATE stories SET comment_count = CASE id` + cases.String() + ` END, updated_at = updated_at WHERE ` + where
+	_, err = conn(ctx, s.db).ExecContext(ctx, query, append(args, whereArgs...)...)
+	return err
+}
+
+func (s *StoryRepo) FindRevisions(ctx context.Context, storyId int64) ([]*model.StoryRevision, error) {
+	res, err := conn(ctx, s.db).QueryContext(ctx, `SELECT id, story_id, revision, title, content, thumbnail_url, category_id, created_at FROM story_revisions WHERE story_id = ? ORDER BY revision DESC`, storyId)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	var revisions []*model.StoryRevision
+	for res.Next() {
+		revision, err := scanStoryRevision(res)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, nil
+}
+
+func (s *StoryRepo) FindRevision(ctx context.Context, storyId int64, revision int64) (*model.StoryRevision, error) {
+	row := conn(ctx, s.db).QueryRowContext(ctx, `SELECT id, story_id, revision, title, content, thumbnail_url, category_id, created_at FROM story_revisions WHERE story_id = ? AND revision = ?`, storyId, revision)
+
+	storyRevision, err := scanStoryRevision(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.NewNotFoundError("revision")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return storyRevision, nil
+}
+
+func scanStoryRevision(row rowScanner) (*model.StoryRevision, error) {
+	var revision model.StoryRevision
+	if err := row.Scan(&revision.Id, &revision.StoryId, &revision.Revision, &revision.Title, &revision.Content, &revision.ThumbnailUrl, &revision.CategoryId, &revision.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
 }
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+)
+
+func TestStoryRepoFindByIdNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Soft deleted stories are filtered out by the query, so they come back
+	// as no rows just like missing ones
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE s.deleted_at IS NULL AND s.id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	story, err := NewStoryRepo(db).FindById(context.Background(), 1)
+	if !errors.Is(err, model.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if story != nil {
+		t.Fatalf("expected no story, got %+v", story)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStoryRepoFindByIdError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	dbErr := errors.New("connection refused")
+	mock.ExpectQuery("FROM stories").WillReturnError(dbErr)
+
+	_, err = NewStoryRepo(db).FindById(context.Background(), 1)
+	if !errors.Is(err, dbErr) {
+		t.Fatalf("expected the database error, got %v", err)
+	}
+	if errors.Is(err, model.ErrNotFound) {
+		t.Fatal("expected a database error not to be reported as not found")
+	}
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+)
+
+type TagRepo struct {
+	db *sql.DB
+}
+
+func NewTagRepo(db *sql.DB) model.ITagRepository {
+	return &TagRepo{
+		db: db,
+	}
+}
+
+// FindAll lists the tags used by published stories, most used first.
+func (t *TagRepo) FindAll(ctx context.Context) ([]*model.Tag, error) {
+	res, err := conn(ctx, t.db).QueryContext(ctx, `SELECT t.id, t.name, t.slug, COUNT(s.id) AS story_count
+		FROM tags AS t
+		JOIN story_tags AS st ON st.tag_id = t.id
+		JOIN stories AS s ON s.id = st.story_id AND s.status = ? AND s.deleted_at IS NULL
+		GROUP BY t.id, t.name, t.slug
+		ORDER BY story_count DESC, t.name ASC`, model.StoryStatusPublished)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	var tags []*model.Tag
+	for res.Next() {
+		var tag model.Tag
+		var storyCount int64
+		if err := res.Scan(&tag.Id, &tag.Name, &tag.Slug, &storyCount); err != nil {
+			return nil, err
+		}
+		tag.StoryCount = &storyCount
+		tags = append(tags, &tag)
+	}
+
+	return tags, nil
+}
+
+func (t *TagRepo) FindBySlug(ctx context.Context, slug string) (*model.Tag, error) {
+	var tag model.Tag
+	err := conn(ctx, t.db).QueryRowContext(ctx, `SELECT id, name, slug FROM tags WHERE slug = ?`, slug).Scan(&tag.Id, &tag.Name, &tag.Slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.NewNotFoundError("tag")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+// FindByStoryIDs returns the tags of each story, keyed by story ID.
+func (t *TagRepo) FindByStoryIDs(ctx context.Context, storyIds []int64) (map[int64][]*model.Tag, error) {
+	tagsByStory := make(map[int64][]*model.Tag)
+	if len(storyIds) == 0 {
+		return tagsByStory, nil
+	}
+
+	query, args, err := sq.Select("st.story_id", "t.id", "t.name", "t.slug").
+		From("story_tags AS st").
+		Join("tags AS t ON t.id = st.tag_id").
+		Where(sq.Eq{"st.story_id": storyIds}).
+		OrderBy("t.name").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := conn(ctx, t.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	for res.Next() {
+		var storyId int64
+		var tag model.Tag
+		if err := res.Scan(&storyId, &tag.Id, &tag.Name, &tag.Slug); err != nil {
+			return nil, err
+		}
+		tagsByStory[storyId] = append(tagsByStory[storyId], &tag)
+	}
+
+	return tagsByStory, nil
+}
+
+// Upsert creates the tags that do not exist yet and returns all of them.
+// Existing tags keep their original name.
+func (t *TagRepo) Upsert(ctx context.Context, tags []model.Tag) ([]*model.Tag, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	insert := sq.Insert("tags").Columns("name", "slug").Suffix("ON DUPLICATE KEY UPDATE id = id")
+	slugs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		insert = insert.Values(tag.Name, tag.Slug)
+		slugs = append(slugs, tag.Slug)
+	}
+
+	query, args, err := insert.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := conn(ctx, t.db).ExecContext(ctx, query, args...); err != nil {
+		return nil, err
+	}
+
+	query, args, err = sq.Select("id", "name", "slug").From("tags").Where(sq.Eq{"slug": slugs}).OrderBy("name").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := conn(ctx, t.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	var upserted []*model.Tag
+	for res.Next() {
+		var tag model.Tag
+		if err := res.Scan(&tag.Id, &tag.Name, &tag.Slug); err != nil {
+			return nil, err
+		}
+		upserted = append(upserted, &tag)
+	}
+
+	return upserted, nil
+}
+
+// SetStoryTags replaces the tags of a story.
+func (t *TagRepo) SetStoryTags(ctx context.Context, storyId int64, tagIds []int64) error {
+	return withTx(ctx, t.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM story_tags WHERE story_id = ?`, storyId); err != nil {
+			return err
+		}
+
+		if len(tagIds) == 0 {
+			return nil
+		}
+
+		insert := sq.Insert("story_tags").Columns("story_id", "tag_id")
+		for _, tagId := range tagIds {
+			insert = insert.Values(storyId, tagId)
+		}
+
+		query, args, err := insert.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, query, args...)
+		return err
+	})
+}
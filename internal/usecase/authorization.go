@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+)
+
+// authorizeStoryMutation allows the story author, editors and admins to
+// change or delete a story.
+func authorizeStoryMutation(ctx context.Context, story *model.Story) error {
+	caller, ok := model.CallerFromContext(ctx)
+	if !ok {
+		return model.ErrUnauthenticated
+	}
+
+	if story.Author.Id == caller.UserID || caller.HasRole(model.RoleAdmin, model.RoleEditor) {
+		return nil
+	}
+
+	return model.ErrForbidden
+}
+
+// authorizeAdmin allows only admins through.
+func authorizeAdmin(ctx context.Context) error {
+	caller, ok := model.CallerFromContext(ctx)
+	if !ok {
+		return model.ErrUnauthenticated
+	}
+
+	if !caller.HasRole(model.RoleAdmin) {
+		return model.ErrForbidden
+	}
+
+	return nil
+}
+
+// authorizeStoryRead lets anyone read published stories. Other stories are
+// visible only to those who may change them and look missing to everyone else.
+func authorizeStoryRead(ctx context.Context, story *model.Story) error {
+	if story.Status == model.StoryStatusPublished {
+		return nil
+	}
+
+	if authorizeStoryMutation(ctx, story) != nil {
+		return model.NewNotFoundError("story")
+	}
+
+	return nil
+}
+
+// authorizeStatusFilter lets authors list their own unpublished stories and
+// editors and admins list anyone's.
+func authorizeStatusFilter(ctx context.Context, filter model.FindAllParam) error {
+	if filter.Status == model.StoryStatusPublished {
+		return nil
+	}
+
+	caller, ok := model.CallerFromContext(ctx)
+	if !ok {
+		return model.ErrUnauthenticated
+	}
+
+	if (filter.UserID > 0 && filter.UserID == caller.UserID) || caller.HasRole(model.RoleAdmin, model.RoleEditor) {
+		return nil
+	}
+
+	return model.ErrForbidden
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+)
+
+// buildCategoryTree nests categories under their parents and returns the top
+// level ones. Categories whose parent is not in the list become top level.
+func buildCategoryTree(categories []*model.Categories) []*model.Categories {
+	byId := make(map[int64]*model.Categories, len(categories))
+	for _, category := range categories {
+		byId[category.Id] = category
+	}
+
+	var roots []*model.Categories
+	for _, category := range categories {
+		if category.ParentId != nil {
+			if parent, ok := byId[*category.ParentId]; ok {
+				parent.Children = append(parent.Children, category)
+				continue
+			}
+		}
+		roots = append(roots, category)
+	}
+
+	return roots
+}
+
+// descendantIDs returns ids together with the IDs of all their descendants.
+func descendantIDs(categories []*model.Categories, ids []int64) []int64 {
+	children := make(map[int64][]int64)
+	for _, category := range categories {
+		if category.ParentId != nil {
+			children[*category.ParentId] = append(children[*category.ParentId], category.Id)
+		}
+	}
+
+	seen := make(map[int64]bool)
+	var result []int64
+	queue := append([]int64(nil), ids...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+		queue = append(queue, children[id]...)
+	}
+
+	return result
+}
+
+// checkParent rejects a parent that does not exist or that would put the
+// category inside its own subtree. id is zero for new categories. The parent's
+// ancestor chain stays locked until the end of the transaction in ctx, so
+// concurrent re-parents cannot create a cycle between them.
+func (c *CategoryUsecase) checkParent(ctx context.Context, id int64, parentId *int64) error {
+	if parentId == nil {
+		return nil
+	}
+
+	if *parentId == id {
+		return model.NewUnprocessableError("parent_id", "must not be the category itself")
+	}
+
+	ancestors, err := c.CategoryRepo.FindAncestorIDs(ctx, *parentId)
+	if errors.Is(err, model.ErrNotFound) {
+		return model.NewUnprocessableError("parent_id", "refers to a category that does not exist")
+	}
+	if err != nil {
+		return err
+	}
+
+	// Meeting the category among the new parent's ancestors means a cycle
+	for _, ancestor := range ancestors {
+		if id != 0 && ancestor == id {
+			return model.NewUnprocessableError("parent_id", "must not be one of the category's descendants")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+)
+
+func parentOf(id int64) *int64 {
+	return &id
+}
+
+// testCategories is the tree
+//
+//	1
+//	├── 2
+//	│   └── 4
+//	└── 3
+//	5
+func testCategories() []*model.Categories {
+	return []*model.Categories{
+		{Id: 1, Name: "News"},
+		{Id: 2, Name: "Tech", ParentId: parentOf(1)},
+		{Id: 3, Name: "Sports", ParentId: parentOf(1)},
+		{Id: 4, Name: "Go", ParentId: parentOf(2)},
+		{Id: 5, Name: "Opinion"},
+	}
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	tests := []struct {
+		name       string
+		categories []*model.Categories
+		want       map[int64][]int64
+		roots      []int64
+	}{
+		{
+			name:       "nested",
+			categories: testCategories(),
+			want:       map[int64][]int64{1: {2, 3}, 2: {4}},
+			roots:      []int64{1, 5},
+		},
+		{
+			name:       "missing parent",
+			categories: []*model.Categories{{Id: 2, ParentId: parentOf(1)}, {Id: 3, ParentId: parentOf(2)}},
+			want:       map[int64][]int64{2: {3}},
+			roots:      []int64{2},
+		},
+		{
+			name:       "empty",
+			categories: nil,
+			want:       map[int64][]int64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roots := buildCategoryTree(tt.categories)
+
+			var rootIds []int64
+			for _, root := range roots {
+				rootIds = append(rootIds, root.Id)
+			}
+			if !reflect.DeepEqual(rootIds, tt.roots) {
+				t.Errorf("expected roots %v, got %v", tt.roots, rootIds)
+			}
+
+			children := make(map[int64][]int64)
+			for _, category := range tt.categories {
+				for _, child := range category.Children {
+					children[category.Id] = append(children[category.Id], child.Id)
+				}
+			}
+			if !reflect.DeepEqual(children, tt.want) {
+				t.Errorf("expected children %v, got %v", tt.want, children)
+			}
+		})
+	}
+}
+
+func TestDescendantIDs(t *testing.T) {
+	tests := []struct {
+		name string
+		ids  []int64
+		want []int64
+	}{
+		{name: "subtree", ids: []int64{1}, want: []int64{1, 2, 3, 4}},
+		{name: "leaf", ids: []int64{4}, want: []int64{4}},
+		{name: "overlapping", ids: []int64{2, 1}, want: []int64{1, 2, 3, 4}},
+		{name: "several", ids: []int64{2, 5}, want: []int64{2, 4, 5}},
+		{name: "unknown", ids: []int64{9}, want: []int64{9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := descendantIDs(testCategories(), tt.ids)
+			sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCheckParent(t *testing.T) {
+	categories := make(map[int64]*model.Categories)
+	for _, category := range testCategories() {
+		categories[category.Id] = category
+	}
+	usecase := &CategoryUsecase{CategoryRepo: &stubCategoryRepo{categories: categories}}
+
+	tests := []struct {
+		name     string
+		id       int64
+		parentId *int64
+		valid    bool
+	}{
+		{name: "top level", id: 2, parentId: nil, valid: true},
+		{name: "new category", id: 0, parentId: parentOf(4), valid: true},
+		{name: "other subtree", id: 2, parentId: parentOf(5), valid: true},
+		{name: "sibling", id: 2, parentId: parentOf(3), valid: true},
+		{name: "itself", id: 2, parentId: parentOf(2), valid: false},
+		{name: "child", id: 2, parentId: parentOf(4), valid: false},
+		{name: "grandchild", id: 1, parentId: parentOf(4), valid: false},
+		{name: "missing parent", id: 2, parentId: parentOf(9), valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := usecase.checkParent(context.Background(), tt.id, tt.parentId)
+			if tt.valid && err != nil {
+				t.Fatalf("expected the parent to be accepted, got %v", err)
+			}
+			if !tt.valid && !errors.Is(err, model.ErrUnprocessable) {
+				t.Fatalf("expected ErrUnprocessable, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDropUnchangedCategoryParent(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  *int64
+		parentId int64
+		changed  bool
+	}{
+		{name: "same parent", current: parentOf(1), parentId: 1, changed: false},
+		{name: "new parent", current: parentOf(1), parentId: 5, changed: true},
+		{name: "already top level", current: nil, parentId: 0, changed: false},
+		{name: "to top level", current: parentOf(1), parentId: 0, changed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := dropUnchangedCategoryFields(&model.Categories{Id: 2, ParentId: tt.current}, model.PatchCategoryInput{ParentId: &tt.parentId})
+			if changed := in.ParentId != nil; changed != tt.changed {
+				t.Errorf("expected changed %v, got %v", tt.changed, changed)
+			}
+		})
+	}
+}
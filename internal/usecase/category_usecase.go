@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/kodinggo/gb-2-api-story-service/internal/model"
 	"github.com/sirupsen/logrus"
@@ -9,20 +11,30 @@ import (
 
 type CategoryUsecase struct {
 	CategoryRepo model.ICategoryRepository
+	StoryRepo    model.IStoryRepository
+	Transactor   model.ITransactor
 }
 
-func NewCategoryUsecase(categoryRepo model.ICategoryRepository) model.ICategoryUsecase {
+func NewCategoryUsecase(categoryRepo model.ICategoryRepository, storyRepo model.IStoryRepository, transactor model.ITransactor) model.ICategoryUsecase {
 	return &CategoryUsecase{
 		CategoryRepo: categoryRepo,
+		StoryRepo:    storyRepo,
+		Transactor:   transactor,
 	}
 }
 
-func (c *CategoryUsecase) FindAll(ctx context.Context) ([]*model.Categories, error) {
+func (c *CategoryUsecase) FindAll(ctx context.Context, param model.FindCategoriesParam) ([]*model.Categories, error) {
 	log := logrus.WithFields(logrus.Fields{
-		"ctx": ctx,
+		"ctx":        ctx,
+		"with_stats": param.WithStats,
 	})
 
-	categories, err := c.CategoryRepo.FindAll(ctx)
+	findAll := c.CategoryRepo.FindAll
+	if param.WithStats {
+		findAll = c.CategoryRepo.FindAllWithStats
+	}
+
+	categories, err := findAll(ctx)
 	if err != nil {
 		log.Error(err)
 		return nil, err
@@ -31,6 +43,17 @@ func (c *CategoryUsecase) FindAll(ctx context.Context) ([]*model.Categories, err
 	return categories, nil
 }
 
+// FindTree returns the top level categories with their subcategories nested
+// under Children.
+func (c *CategoryUsecase) FindTree(ctx context.Context, param model.FindCategoriesParam) ([]*model.Categories, error) {
+	categories, err := c.FindAll(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCategoryTree(categories), nil
+}
+
 func (c *CategoryUsecase) FindById(ctx context.Context, id int64) (*model.Categories, error) {
 	log := logrus.WithFields(logrus.Fields{
 		"ctx": ctx,
@@ -46,65 +69,317 @@ func (c *CategoryUsecase) FindById(ctx context.Context, id int64) (*model.Catego
 	return category, nil
 }
 
-func (c *CategoryUsecase) Create(ctx context.Context, category model.Categories) error {
+// FindBySlug looks a category up by its current or an old slug. When found by
+// an old slug, the returned category's Slug differs from the one asked for.
+func (c *CategoryUsecase) FindBySlug(ctx context.Context, slug string) (*model.Categories, error) {
+	log := logrus.WithFields(logrus.Fields{
+		"ctx":  ctx,
+		"slug": slug,
+	})
+
+	category, err := c.CategoryRepo.FindBySlug(ctx, slug)
+	if errors.Is(err, model.ErrNotFound) {
+		id, redirectErr := c.CategoryRepo.FindSlugRedirect(ctx, slug)
+		if redirectErr != nil {
+			log.Error(redirectErr)
+			return nil, redirectErr
+		}
+		category, err = c.CategoryRepo.FindById(ctx, id)
+	}
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (c *CategoryUsecase) Create(ctx context.Context, category model.Categories) (*model.Categories, error) {
 	log := logrus.WithFields(logrus.Fields{
 		"ctx":  ctx,
 		"name": category.Name,
 	})
 
-	err := v.StructCtx(ctx, category)
+	err := authorizeAdmin(ctx)
 	if err != nil {
 		log.Error(err)
-		return err
+		return nil, err
+	}
+
+	err = validate(ctx, category)
+	if err != nil {
+		log.Error(err)
+		return nil, err
 	}
 
 	newCategory := model.Categories{
-		Id:   category.Id,
-		Name: category.Name,
+		Id:          category.Id,
+		Name:        category.Name,
+		ParentId:    category.ParentId,
+		Description: category.Description,
+		IconUrl:     category.IconUrl,
+		Color:       category.Color,
+		Position:    category.Position,
 	}
 
-	err = c.CategoryRepo.Create(ctx, newCategory)
+	var created *model.Categories
+	err = c.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+		err := c.checkParent(ctx, 0, category.ParentId)
+		if err != nil {
+			return err
+		}
+
+		created, err = c.CategoryRepo.Create(ctx, newCategory)
+		return err
+	})
 	if err != nil {
 		log.Error(err)
-		return err
+		return nil, err
 	}
 
-	return nil
+	return created, nil
 }
 
-func (c *CategoryUsecase) Update(ctx context.Context, category model.Categories) error {
+func (c *CategoryUsecase) Update(ctx context.Context, category model.Categories) (*model.Categories, error) {
 	log := logrus.WithFields(logrus.Fields{
 		"ctx":  ctx,
 		"name": category.Name,
 	})
 
-	err := v.StructCtx(ctx, category)
+	err := authorizeAdmin(ctx)
 	if err != nil {
 		log.Error(err)
-		return err
+		return nil, err
+	}
+
+	err = validate(ctx, category)
+	if err != nil {
+		log.Error(err)
+		return nil, err
 	}
 
 	newCategory := model.Categories{
-		Id:   category.Id,
-		Name: category.Name,
+		Id:          category.Id,
+		Name:        category.Name,
+		ParentId:    category.ParentId,
+		Description: category.Description,
+		IconUrl:     category.IconUrl,
+		Color:       category.Color,
+		Position:    category.Position,
 	}
 
-	err = c.CategoryRepo.Update(ctx, newCategory)
+	var updated *model.Categories
+	err = c.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+		_, err := c.CategoryRepo.FindById(ctx, category.Id)
+		if err != nil {
+			return err
+		}
+
+		err = c.checkParent(ctx, category.Id, category.ParentId)
+		if err != nil {
+			return err
+		}
+
+		updated, err = c.CategoryRepo.Update(ctx, newCategory)
+		return err
+	})
 	if err != nil {
 		log.Error(err)
-		return err
+		return nil, err
 	}
 
-	return nil
+	return updated, nil
 }
 
-func (c *CategoryUsecase) Delete(ctx context.Context, id int64) error {
+func (c *CategoryUsecase) Patch(ctx context.Context, id int64, in model.PatchCategoryInput) (*model.Categories, error) {
 	log := logrus.WithFields(logrus.Fields{
 		"ctx": ctx,
 		"id":  id,
 	})
 
-	err := c.CategoryRepo.Delete(ctx, id)
+	err := authorizeAdmin(ctx)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = validate(ctx, in)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	var patched *model.Categories
+	err = c.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+		category, err := c.CategoryRepo.FindById(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		in := dropUnchangedCategoryFields(category, in)
+		if in.IsEmpty() {
+			patched = category
+			return nil
+		}
+
+		if in.ParentId != nil && *in.ParentId > 0 {
+			err = c.checkParent(ctx, id, in.ParentId)
+			if err != nil {
+				return err
+			}
+		}
+
+		patched, err = c.CategoryRepo.Patch(ctx, id, in)
+		return err
+	})
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return patched, nil
+}
+
+// dropUnchangedCategoryFields removes the fields of a patch that already hold
+// the requested value.
+func dropUnchangedCategoryFields(category *model.Categories, in model.PatchCategoryInput) model.PatchCategoryInput {
+	if in.Name != nil && *in.Name == category.Name {
+		in.Name = nil
+	}
+	if in.ParentId != nil {
+		current := int64(0)
+		if category.ParentId != nil {
+			current = *category.ParentId
+		}
+		if *in.ParentId == current {
+			in.ParentId = nil
+		}
+	}
+	if in.Description != nil && *in.Description == category.Description {
+		in.Description = nil
+	}
+	if in.IconUrl != nil && *in.IconUrl == category.IconUrl {
+		in.IconUrl = nil
+	}
+	if in.Color != nil && *in.Color == category.Color {
+		in.Color = nil
+	}
+	if in.Position != nil && category.Position != nil && *in.Position == *category.Position {
+		in.Position = nil
+	}
+
+	return in
+}
+
+// Reorder sets the positions of several categories in one transaction and
+// returns all categories in their new order.
+func (c *CategoryUsecase) Reorder(ctx context.Context, in model.ReorderCategoriesInput) ([]*model.Categories, error) {
+	log := logrus.WithFields(logrus.Fields{
+		"ctx":       ctx,
+		"positions": in.Positions,
+	})
+
+	err := authorizeAdmin(ctx)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = validate(ctx, in)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = c.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+		for _, position := range in.Positions {
+			if _, err := c.CategoryRepo.FindById(ctx, position.Id); err != nil {
+				return err
+			}
+
+			if err := c.CategoryRepo.UpdatePosition(ctx, position.Id, position.Position); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return c.FindAll(ctx, model.FindCategoriesParam{})
+}
+
+// Delete soft deletes the category. Its stories are moved to in.ReassignTo
+// in the same transaction; without a target, a category that still has
+// stories is not deleted. Subcategories move up to the deleted category's
+// parent.
+func (c *CategoryUsecase) Delete(ctx context.Context, id int64, in model.DeleteCategoryInput) error {
+	log := logrus.WithFields(logrus.Fields{
+		"ctx":         ctx,
+		"id":          id,
+		"reassign_to": in.ReassignTo,
+	})
+
+	err := authorizeAdmin(ctx)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	err = validate(ctx, in)
+	if err != nil {
+		log.Error("Validation error:", err)
+		return err
+	}
+
+	if in.ReassignTo == id {
+		return model.NewUnprocessableError("reassign_to", "must be a different category")
+	}
+
+	err = c.Transactor.WithinTx(ctx, func(ctx context.Context) error {
+		// Stories written meanwhile wait for the lock, then find the category
+		// gone, so none is left pointing at it
+		category, err := c.CategoryRepo.FindByIdForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if in.ReassignTo > 0 {
+			_, err := c.CategoryRepo.FindByIdForShare(ctx, in.ReassignTo)
+			if errors.Is(err, model.ErrNotFound) {
+				return model.NewUnprocessableError("reassign_to", "refers to a category that does not exist")
+			}
+			if err != nil {
+				return err
+			}
+
+			if _, err := c.StoryRepo.ReassignCategory(ctx, id, in.ReassignTo); err != nil {
+				return err
+			}
+
+			if err := c.CategoryRepo.RefreshStats(ctx, in.ReassignTo); err != nil {
+				return err
+			}
+		} else {
+			count, err := c.StoryRepo.CountByCategory(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			if count > 0 {
+				return model.NewConflictError(fmt.Sprintf("category is used by %d stories, pass reassign_to to move them to another category", count))
+			}
+		}
+
+		if err := c.CategoryRepo.MoveChildren(ctx, id, category.ParentId); err != nil {
+			return err
+		}
+
+		return c.CategoryRepo.Delete(ctx, id)
+	})
 	if err != nil {
 		log.Error(err)
 		return err
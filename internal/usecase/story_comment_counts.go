@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/kodinggo/gb-2-api-comment-service/pb/comment_service"
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// commentCountBatchSize is the number of stories whose comments are counted
+// per call to the comment service.
+const commentCountBatchSize = 100
+
+// SyncCommentCounts recounts the comments of every story and stores the counts
+// that changed. Reads show the stored counts and the most commented sort
+// orders by them, so this is what keeps them fresh. The comment service has no
+// call to count comments, so they are fetched and counted here, in the
+// background rather than on every read. It is run periodically by the server
+// and returns the number of counts updated.
+func (s *StoryUsecase) SyncCommentCounts(ctx context.Context) (int64, error) {
+	log := logrus.WithFields(logrus.Fields{
+		"ctx": ctx,
+	})
+
+	var updated int64
+	var afterId int64
+	for {
+		stored, lastId, err := s.storyRepo.FindCommentCounts(ctx, afterId, commentCountBatchSize)
+		if err != nil {
+			log.Error("Error fetching comment counts: ", err)
+			return updated, err
+		}
+		if len(stored) == 0 {
+			return updated, nil
+		}
+
+		storyIds := make([]int64, 0, len(stored))
+		for id := range stored {
+			storyIds = append(storyIds, id)
+		}
+
+		commentPb, err := s.grpcCommentClient.FindAllByStoryIDs(ctx, &comment_service.FindAllByStoryIDsRequest{
+			StoryId: storyIds,
+		})
+		if err != nil {
+			log.Error("Error fetching comments: ", err)
+			return updated, model.NewUnavailableError("comment service", err)
+		}
+
+		counts := make(map[int64]int64, len(stored))
+		for _, comment := range commentPb.GetComments() {
+			counts[comment.StoryId]++
+		}
+
+		changed := make(map[int64]int64)
+		for id, count := range stored {
+			if counts[id] != count {
+				changed[id] = counts[id]
+			}
+		}
+
+		if err := s.storyRepo.UpdateCommentCounts(ctx, changed); err != nil {
+			log.Error("Error updating comment counts: ", err)
+			return updated, err
+		}
+		updated += int64(len(changed))
+
+		afterId = lastId
+	}
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kodinggo/gb-2-api-comment-service/pb/comment_service"
+	"github.com/kodinggo/gb-2-api-story-service/internal/helper"
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// FindComments pages through the comments of a story, newest first. The
+// comment service returns every comment of the story, so the page is cut here.
+func (s *StoryUsecase) FindComments(ctx context.Context, storyId int64, param model.FindCommentsParam) ([]*model.Comment, *model.Pagination, error) {
+	if param.Limit <= 0 {
+		param.Limit = model.DefaultLimit
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"ctx":      ctx,
+		"story_id": storyId,
+		"limit":    param.Limit,
+	})
+
+	err := validate(ctx, param)
+	if err != nil {
+		log.Error("Validation error:", err)
+		return nil, nil, err
+	}
+
+	story, err := s.storyRepo.FindById(ctx, storyId)
+	if err != nil {
+		log.Error("Error fetching story:", err)
+		return nil, nil, err
+	}
+
+	err = authorizeStoryRead(ctx, story)
+	if err != nil {
+		log.Error("Unauthorized story read:", err)
+		return nil, nil, err
+	}
+
+	commentPb, err := s.grpcCommentClient.FindAllByStoryID(ctx, &comment_service.FindAllByStoryIDRequest{
+		StoryId: storyId,
+	})
+	if err != nil {
+		log.Error("Error fetching comments:", err)
+		return nil, nil, model.NewUnavailableError("comment service", err)
+	}
+
+	comments := helper.ConvertPbCommentToModelComments(commentPb.GetComments())
+	sortCommentsNewestFirst(comments)
+
+	if param.Cursor != nil {
+		start := sort.Search(len(comments), func(i int) bool {
+			return commentBefore(comments[i], param.Cursor)
+		})
+		comments = comments[start:]
+	}
+
+	pagination := &model.Pagination{HasMore: int64(len(comments)) > param.Limit}
+	if pagination.HasMore {
+		comments = comments[:param.Limit]
+		last := comments[len(comments)-1]
+		pagination.NextCursor = model.CommentCursor{CreatedAt: last.CreatedAt, Id: last.ID}.Encode()
+	}
+
+	return comments, pagination, nil
+}
+
+// latestComments returns at most limit comments, newest first.
+func latestComments(comments []*model.Comment, limit int64) []*model.Comment {
+	sortCommentsNewestFirst(comments)
+	if int64(len(comments)) > limit {
+		comments = comments[:limit]
+	}
+
+	return comments
+}
+
+func sortCommentsNewestFirst(comments []*model.Comment) {
+	sort.Slice(comments, func(i, j int) bool {
+		if !comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+			return comments[i].CreatedAt.After(comments[j].CreatedAt)
+		}
+		return comments[i].ID > comments[j].ID
+	})
+}
+
+// commentBefore reports whether the comment comes after the cursor in a
+// newest first listing.
+func commentBefore(comment *model.Comment, cursor *model.CommentCursor) bool {
+	if !comment.CreatedAt.Equal(cursor.CreatedAt) {
+		return comment.CreatedAt.Before(cursor.CreatedAt)
+	}
+	return comment.ID < cursor.Id
+}
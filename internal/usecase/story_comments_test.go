@@ -0,0 +1,352 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kodinggo/gb-2-api-comment-service/pb/comment_service"
+	"github.com/kodinggo/gb-2-api-story-service/internal/fake"
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+	"github.com/kodinggo/gb-2-api-story-service/internal/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// stubStoryRepo serves stories from memory and records stored comment counts.
+// Methods not overridden panic through the nil embedded interface.
+type stubStoryRepo struct {
+	model.IStoryRepository
+
+	mu      sync.Mutex
+	stories map[int64]*model.Story
+}
+
+func newStubStoryRepo(stories ...*model.Story) *stubStoryRepo {
+	repo := &stubStoryRepo{stories: make(map[int64]*model.Story)}
+	for _, story := range stories {
+		repo.stories[story.Id] = story
+	}
+	return repo
+}
+
+// FindAll returns copies of every story ordered by ID, ignoring the filter.
+func (r *stubStoryRepo) FindAll(_ context.Context, _ model.FindAllParam) ([]*model.Story, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stories := make([]*model.Story, 0, len(r.stories))
+	for _, story := range r.stories {
+		stored := *story
+		stories = append(stories, &stored)
+	}
+	sort.Slice(stories, func(i, j int) bool { return stories[i].Id < stories[j].Id })
+
+	return stories, nil
+}
+
+func (r *stubStoryRepo) FindById(_ context.Context, id int64) (*model.Story, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	story, ok := r.stories[id]
+	if !ok {
+		return nil, model.NewNotFoundError("story")
+	}
+	stored := *story
+	return &stored, nil
+}
+
+func (r *stubStoryRepo) FindByIDs(_ context.Context, ids []int64) ([]*model.Story, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var stories []*model.Story
+	for _, id := range ids {
+		if story, ok := r.stories[id]; ok {
+			stored := *story
+			stories = append(stories, &stored)
+		}
+	}
+	return stories, nil
+}
+
+func (r *stubStoryRepo) FindCommentCounts(_ context.Context, afterId int64, limit int64) (map[int64]int64, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := make([]int64, 0, len(r.stories))
+	for id := range r.stories {
+		if id > afterId {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	if int64(len(ids)) > limit {
+		ids = ids[:limit]
+	}
+
+	counts := make(map[int64]int64, len(ids))
+	var lastId int64
+	for _, id := range ids {
+		counts[id] = r.stories[id].CommentCount
+		lastId = id
+	}
+
+	return counts, lastId, nil
+}
+
+func (r *stubStoryRepo) UpdateCommentCounts(_ context.Context, counts map[int64]int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, count := range counts {
+		r.stories[id].CommentCount = count
+	}
+	return nil
+}
+
+func (r *stubStoryRepo) commentCount(id int64) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.stories[id].CommentCount
+}
+
+// stubTagRepo has no tags.
+type stubTagRepo struct {
+	model.ITagRepository
+}
+
+func (stubTagRepo) FindByStoryIDs(_ context.Context, _ []int64) (map[int64][]*model.Tag, error) {
+	return map[int64][]*model.Tag{}, nil
+}
+
+// newCommentTestUsecase builds a StoryUsecase whose comments are served by
+// comments through the resilient client used in production.
+func newCommentTestUsecase(t *testing.T, comments *fake.CommentServer, stories *stubStoryRepo) *StoryUsecase {
+	t.Helper()
+
+	client, stop, err := comments.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(stop)
+
+	return &StoryUsecase{
+		storyRepo: stories,
+		grpcCommentClient: repository.NewCommentClient(client, repository.CommentClientOptions{
+			Timeout:          time.Second,
+			MaxRetries:       1,
+			RetryBackoff:     time.Millisecond,
+			BreakerThreshold: 1,
+			BreakerCooldown:  time.Minute,
+		}),
+		accountClient: fake.NewAccountClient(),
+		tagRepo:       stubTagRepo{},
+	}
+}
+
+// commentTestData returns two published stories with stale stored comment
+// counts, and comments on the first one posted a minute apart.
+func commentTestData() (*fake.CommentServer, *stubStoryRepo) {
+	posted := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	comments := fake.NewCommentServer()
+	for i := 0; i < 5; i++ {
+		comments.Add(&comment_service.Comment{
+			StoryId:   1,
+			Comment:   "comment",
+			CreatedAt: timestamppb.New(posted.Add(time.Duration(i) * time.Minute)),
+		})
+	}
+
+	stories := newStubStoryRepo(
+		&model.Story{Id: 1, Title: "First", Status: model.StoryStatusPublished, CommentCount: 2},
+		&model.Story{Id: 2, Title: "Second", Status: model.StoryStatusPublished, CommentCount: 1},
+	)
+
+	return comments, stories
+}
+
+func commentIDs(comments []*model.Comment) []int64 {
+	ids := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+	}
+	return ids
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFindAllIncludeComments(t *testing.T) {
+	comments, stories := commentTestData()
+	usecase := newCommentTestUsecase(t, comments, stories)
+
+	res, _, err := usecase.FindAll(context.Background(), model.FindAllParam{IncludeComments: true, CommentsLimit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 {
+		t.Fatalf("expected 2 stories, got %d", len(res))
+	}
+
+	if ids := commentIDs(res[0].Comments); !equalIDs(ids, []int64{5, 4}) {
+		t.Errorf("expected the 2 latest comments, got %v", ids)
+	}
+	if len(res[1].Comments) != 0 {
+		t.Errorf("expected no comments on the second story, got %d", len(res[1].Comments))
+	}
+
+	if res[0].CommentCount != 5 || res[1].CommentCount != 0 {
+		t.Errorf("expected comment counts 5 and 0, got %d and %d", res[0].CommentCount, res[1].CommentCount)
+	}
+	// Reads leave the stored counts to SyncCommentCounts
+	if stories.commentCount(1) != 2 || stories.commentCount(2) != 1 {
+		t.Errorf("expected stored comment counts 2 and 1, got %d and %d", stories.commentCount(1), stories.commentCount(2))
+	}
+}
+
+// failingComments makes every comment service call fail, so tests can check
+// the comment service is not called at all.
+func failingComments(comments *fake.CommentServer) {
+	comments.Fail(status.Error(codes.Internal, "comment service must not be called"))
+}
+
+func TestFindAllWithoutComments(t *testing.T) {
+	comments, stories := commentTestData()
+	usecase := newCommentTestUsecase(t, comments, stories)
+	failingComments(comments)
+
+	res, _, err := usecase.FindAll(context.Background(), model.FindAllParam{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Listings show the stored counts without calling the comment service
+	if res[0].Comments != nil || res[0].CommentsUnavailable {
+		t.Errorf("expected no comments and no comment service call, got %+v", res[0])
+	}
+	if res[0].CommentCount != 2 {
+		t.Errorf("expected the stored comment count 2, got %d", res[0].CommentCount)
+	}
+}
+
+func TestFindByIdStoredCount(t *testing.T) {
+	comments, stories := commentTestData()
+	usecase := newCommentTestUsecase(t, comments, stories)
+	failingComments(comments)
+
+	story, err := usecase.FindById(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Comments are paged through FindComments instead
+	if story.Comments != nil || story.CommentsUnavailable {
+		t.Errorf("expected no comments and no comment service call, got %+v", story)
+	}
+	if story.CommentCount != 2 {
+		t.Errorf("expected the stored comment count 2, got %d", story.CommentCount)
+	}
+}
+
+func TestFindCommentsPaging(t *testing.T) {
+	comments, stories := commentTestData()
+	usecase := newCommentTestUsecase(t, comments, stories)
+
+	var pages [][]int64
+	param := model.FindCommentsParam{Limit: 2}
+	for {
+		page, pagination, err := usecase.FindComments(context.Background(), 1, param)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages = append(pages, commentIDs(page))
+
+		if !pagination.HasMore {
+			break
+		}
+		param.Cursor, err = model.DecodeCommentCursor(pagination.NextCursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := [][]int64{{5, 4}, {3, 2}, {1}}
+	if len(pages) != len(expected) {
+		t.Fatalf("expected pages %v, got %v", expected, pages)
+	}
+	for i := range expected {
+		if !equalIDs(pages[i], expected[i]) {
+			t.Fatalf("expected pages %v, got %v", expected, pages)
+		}
+	}
+}
+
+func TestCommentsUnavailable(t *testing.T) {
+	comments, stories := commentTestData()
+	usecase := newCommentTestUsecase(t, comments, stories)
+
+	comments.Fail(status.Error(codes.Unavailable, "comment service is down"))
+
+	// Listings degrade to their stored counts
+	res, _, err := usecase.FindAll(context.Background(), model.FindAllParam{IncludeComments: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, story := range res {
+		if !story.CommentsUnavailable || story.Comments != nil {
+			t.Errorf("expected story %d to be marked without comments", story.Id)
+		}
+	}
+	if res[0].CommentCount != 2 {
+		t.Errorf("expected the stored comment count 2, got %d", res[0].CommentCount)
+	}
+
+	// A comment page has nothing to degrade to
+	_, _, err = usecase.FindComments(context.Background(), 1, model.FindCommentsParam{})
+	if !errors.Is(err, model.ErrUnavailable) {
+		t.Fatalf("expected ErrUnavailable, got %v", err)
+	}
+
+	// The breaker stays open after the service recovers, until its cooldown
+	comments.Fail(nil)
+	_, _, err = usecase.FindComments(context.Background(), 1, model.FindCommentsParam{})
+	if !errors.Is(err, model.ErrUnavailable) {
+		t.Fatalf("expected the open breaker to keep comments unavailable, got %v", err)
+	}
+	if stories.commentCount(1) != 2 {
+		t.Errorf("expected the stored comment count to be kept, got %d", stories.commentCount(1))
+	}
+}
+
+func TestSyncCommentCounts(t *testing.T) {
+	comments, stories := commentTestData()
+	usecase := newCommentTestUsecase(t, comments, stories)
+
+	updated, err := usecase.SyncCommentCounts(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated != 2 {
+		t.Errorf("expected 2 updated counts, got %d", updated)
+	}
+	if stories.commentCount(1) != 5 || stories.commentCount(2) != 0 {
+		t.Errorf("expected stored comment counts 5 and 0, got %d and %d", stories.commentCount(1), stories.commentCount(2))
+	}
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// storyTransitions lists the statuses each status may move to. Rescheduling a
+// scheduled story is allowed; published stories can only be archived and
+// archived stories go back to draft before being published again.
+var storyTransitions = map[string][]string{
+	model.StoryStatusDraft:     {model.StoryStatusScheduled, model.StoryStatusPublished, model.StoryStatusArchived},
+	model.StoryStatusScheduled: {model.StoryStatusDraft, model.StoryStatusScheduled, model.StoryStatusPublished, model.StoryStatusArchived},
+	model.StoryStatusPublished: {model.StoryStatusArchived},
+	model.StoryStatusArchived:  {model.StoryStatusDraft},
+}
+
+func checkTransition(from, to string) error {
+	for _, allowed := range storyTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+
+	return model.NewUnprocessableError("status", fmt.Sprintf("cannot change from %s to %s", from, to))
+}
+
+// publishedAtFor returns the publication time a story gets when it moves to
+// status. Published stories keep their original publication time.
+func publishedAtFor(status string, publishAt *time.Time, current *time.Time, now time.Time) (*time.Time, error) {
+	switch status {
+	case model.StoryStatusScheduled:
+		if !publishAt.After(now) {
+			return nil, model.NewValidationError("publish_at", "must be in the future")
+		}
+		return publishAt, nil
+	case model.StoryStatusPublished:
+		if current != nil && !current.After(now) {
+			return current, nil
+		}
+		return &now, nil
+	case model.StoryStatusArchived:
+		return current, nil
+	default:
+		return nil, nil
+	}
+}
+
+func (s *StoryUsecase) ChangeStatus(ctx context.Context, id int64, version int64, in model.StoryStatusInput) (*model.Story, error) {
+	log := logrus.WithFields(logrus.Fields{
+		"ctx":    ctx,
+		"id":     id,
+		"status": in.Status,
+	})
+
+	err := validate(ctx, in)
+	if err != nil {
+		log.Error("Validation error:", err)
+		return nil, err
+	}
+
+	story, err := s.storyRepo.FindById(ctx, id)
+	if err != nil {
+		log.Error("Error fetching story:", err)
+		return nil, err
+	}
+
+	err = authorizeStoryMutation(ctx, story)
+	if err != nil {
+		log.Error("Unauthorized story status change:", err)
+		return nil, err
+	}
+
+	err = checkVersion(story, version)
+	if err != nil {
+		log.Error("Stale story status change:", err)
+		return nil, err
+	}
+
+	err = checkTransition(story.Status, in.Status)
+	if err != nil {
+		log.Error("Invalid story status change:", err)
+		return nil, err
+	}
+
+	publishedAt, err := publishedAtFor(in.Status, in.PublishAt, story.PublishedAt, time.Now())
+	if err != nil {
+		log.Error("Validation error:", err)
+		return nil, err
+	}
+
+	updated, err := s.storyRepo.UpdateStatus(ctx, id, version, in.Status, publishedAt)
+	if err != nil {
+		log.Error("Error updating story status:", err)
+		return nil, err
+	}
+
+	s.refreshCategoryStats(ctx, story.Category.Id)
+
+	s.resolveAuthors(ctx, []*model.Story{updated})
+
+	err = s.attachTags(ctx, []*model.Story{updated})
+	if err != nil {
+		log.Error("Error fetching tags:", err)
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// PublishScheduled publishes the scheduled stories whose publication time has
+// come. It is run periodically by the server.
+func (s *StoryUsecase) PublishScheduled(ctx context.Context) (int64, error) {
+	published, err := s.storyRepo.PublishDue(ctx, time.Now())
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx": ctx,
+		}).Error("Error publishing scheduled stories: ", err)
+		return 0, err
+	}
+
+	// The published stories may belong to any category
+	if published > 0 {
+		s.refreshCategoryStats(ctx)
+	}
+
+	return published, nil
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/helper"
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+)
+
+// maxTagSlugLength is the size of the tags.slug column. Slugs can outgrow
+// their names, since e.g. "&" becomes "and".
+const maxTagSlugLength = 64
+
+// normalizeTags trims, collapses whitespace in and lowercases tag names, and
+// drops tags whose slug repeats an earlier one.
+func normalizeTags(names []string) ([]model.Tag, error) {
+	tags := make([]model.Tag, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for i, name := range names {
+		name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+		slug := helper.Slugify(name)
+		if slug == "" {
+			return nil, model.NewValidationError(fmt.Sprintf("tags[%d]", i), "must contain a letter or digit")
+		}
+		if len(slug) > maxTagSlugLength {
+			return nil, model.NewValidationError(fmt.Sprintf("tags[%d]", i), "is too long")
+		}
+
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+
+		tags = append(tags, model.Tag{Name: name, Slug: slug})
+	}
+
+	if len(tags) > model.MaxStoryTags {
+		return nil, model.NewValidationError("tags", fmt.Sprintf("must have at most %d tags", model.MaxStoryTags))
+	}
+
+	return tags, nil
+}
+
+// tagsChanged reports whether tags differ from the current tags of a story,
+// regardless of order.
+func tagsChanged(current []*model.Tag, tags []model.Tag) bool {
+	if len(current) != len(tags) {
+		return true
+	}
+
+	slugs := make(map[string]bool, len(current))
+	for _, tag := range current {
+		slugs[tag.Slug] = true
+	}
+	for _, tag := range tags {
+		if !slugs[tag.Slug] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// saveTags replaces the tags of a story, creating the tags that do not exist
+// yet.
+func (s *StoryUsecase) saveTags(ctx context.Context, storyId int64, tags []model.Tag) error {
+	saved, err := s.tagRepo.Upsert(ctx, tags)
+	if err != nil {
+		return err
+	}
+
+	tagIds := make([]int64, 0, len(saved))
+	for _, tag := range saved {
+		tagIds = append(tagIds, tag.Id)
+	}
+
+	return s.tagRepo.SetStoryTags(ctx, storyId, tagIds)
+}
+
+// attachTags fills in the tags of the stories.
+func (s *StoryUsecase) attachTags(ctx context.Context, stories []*model.Story) error {
+	storyIds := make([]int64, 0, len(stories))
+	for _, story := range stories {
+		storyIds = append(storyIds, story.Id)
+	}
+
+	tagsByStory, err := s.tagRepo.FindByStoryIDs(ctx, storyIds)
+	if err != nil {
+		return err
+	}
+
+	for _, story := range stories {
+		story.Tags = tagsByStory[story.Id]
+		if story.Tags == nil {
+			story.Tags = []*model.Tag{}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tooMany := make([]string, model.MaxStoryTags+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("tag %d", i)
+	}
+
+	tests := []struct {
+		name  string
+		names []string
+		want  []model.Tag
+		field string
+	}{
+		{
+			name:  "whitespace and case",
+			names: []string{"  Machine   Learning ", "GO"},
+			want:  []model.Tag{{Name: "machine learning", Slug: "machine-learning"}, {Name: "go", Slug: "go"}},
+		},
+		{
+			name:  "same slug",
+			names: []string{"R&D", "r and d", "r-and-d"},
+			want:  []model.Tag{{Name: "r&d", Slug: "r-and-d"}},
+		},
+		{
+			name:  "transliterated",
+			names: []string{"Привет"},
+			want:  []model.Tag{{Name: "привет", Slug: "privet"}},
+		},
+		{
+			name:  "empty",
+			names: nil,
+			want:  []model.Tag{},
+		},
+		{
+			name:  "no letters",
+			names: []string{"go", "🙂"},
+			field: "tags[1]",
+		},
+		{
+			name:  "slug too long",
+			names: []string{strings.Repeat("&", maxTagSlugLength)},
+			field: "tags[0]",
+		},
+		{
+			name:  "too many",
+			names: tooMany,
+			field: "tags",
+		},
+		{
+			name:  "duplicates within the limit",
+			names: append([]string{"go", "Go", "GO"}, tooMany[:model.MaxStoryTags-1]...),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeTags(tt.names)
+			if tt.field != "" {
+				var validationErr *model.ValidationError
+				if !errors.As(err, &validationErr) || validationErr.Fields[tt.field] == "" {
+					t.Fatalf("expected a validation error for %s, got %v", tt.field, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTagsChanged(t *testing.T) {
+	current := []*model.Tag{{Id: 1, Name: "go", Slug: "go"}, {Id: 2, Name: "web", Slug: "web"}}
+
+	tests := []struct {
+		name    string
+		tags    []model.Tag
+		changed bool
+	}{
+		{name: "same", tags: []model.Tag{{Slug: "go"}, {Slug: "web"}}, changed: false},
+		{name: "reordered", tags: []model.Tag{{Slug: "web"}, {Slug: "go"}}, changed: false},
+		{name: "added", tags: []model.Tag{{Slug: "go"}, {Slug: "web"}, {Slug: "api"}}, changed: true},
+		{name: "removed", tags: []model.Tag{{Slug: "go"}}, changed: true},
+		{name: "replaced", tags: []model.Tag{{Slug: "go"}, {Slug: "api"}}, changed: true},
+		{name: "cleared", tags: []model.Tag{}, changed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if changed := tagsChanged(current, tt.tags); changed != tt.changed {
+				t.Errorf("expected changed %v, got %v", tt.changed, changed)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// FindTrash lists soft deleted stories for admins.
+func (s *StoryUsecase) FindTrash(ctx context.Context, limit int64, page int64) ([]*model.Story, *model.Pagination, error) {
+	if limit <= 0 {
+		limit = model.DefaultLimit
+	}
+
+	if page <= 0 {
+		page = model.DefaultPage
+	}
+
+	log := logrus.WithFields(logrus.Fields{
+		"ctx":   ctx,
+		"limit": limit,
+		"page":  page,
+	})
+
+	err := authorizeAdmin(ctx)
+	if err != nil {
+		log.Error("Unauthorized trash listing:", err)
+		return nil, nil, err
+	}
+
+	// Fetch one extra story to find out whether another page follows
+	stories, err := s.storyRepo.FindTrash(ctx, limit+1, (page-1)*limit)
+	if err != nil {
+		log.Error("Error fetching deleted stories:", err)
+		return nil, nil, err
+	}
+
+	pagination := &model.Pagination{HasMore: int64(len(stories)) > limit}
+	if pagination.HasMore {
+		stories = stories[:limit]
+	}
+
+	s.resolveAuthors(ctx, stories)
+
+	err = s.attachTags(ctx, stories)
+	if err != nil {
+		log.Error("Error fetching tags:", err)
+		return nil, nil, err
+	}
+
+	return stories, pagination, nil
+}
+
+// RestoreDeleted undoes the soft delete of a story for admins, moving it to
+// categoryId unless that is 0. Stories whose category was deleted meanwhile
+// cannot be restored without moving them.
+func (s *StoryUsecase) RestoreDeleted(ctx context.Context, id int64, categoryId int64) (*model.Story, error) {
+	log := logrus.WithFields(logrus.Fields{
+		"ctx":         ctx,
+		"id":          id,
+		"category_id": categoryId,
+	})
+
+	err := authorizeAdmin(ctx)
+	if err != nil {
+		log.Error("Unauthorized story restore:", err)
+		return nil, err
+	}
+
+	story, err := s.storyRepo.Restore(ctx, id, categoryId)
+	if err != nil {
+		log.Error("Error restoring story:", err)
+		return nil, err
+	}
+
+	s.refreshCategoryStats(ctx, story.Category.Id)
+
+	s.resolveAuthors(ctx, []*model.Story{story})
+
+	err = s.attachTags(ctx, []*model.Story{story})
+	if err != nil {
+		log.Error("Error fetching tags:", err)
+		return nil, err
+	}
+
+	return story, nil
+}
+
+// Purge permanently deletes a soft deleted story for admins.
+func (s *StoryUsecase) Purge(ctx context.Context, id int64) error {
+	log := logrus.WithFields(logrus.Fields{
+		"ctx": ctx,
+		"id":  id,
+	})
+
+	err := authorizeAdmin(ctx)
+	if err != nil {
+		log.Error("Unauthorized story purge:", err)
+		return err
+	}
+
+	err = s.storyRepo.Purge(ctx, id)
+	if err != nil {
+		log.Error("Error purging story:", err)
+		return err
+	}
+
+	return nil
+}
+
+// PurgeExpired permanently deletes stories that have been soft deleted for
+// longer than retention. It is meant for maintenance jobs and does not check
+// the caller.
+func (s *StoryUsecase) PurgeExpired(ctx context.Context, retention time.Duration) (int64, error) {
+	log := logrus.WithFields(logrus.Fields{
+		"ctx":       ctx,
+		"retention": retention,
+	})
+
+	if retention <= 0 {
+		log.Error("Invalid retention period")
+		return 0, model.NewValidationError("retention", "must be positive")
+	}
+
+	purged, err := s.storyRepo.PurgeDeletedBefore(ctx, time.Now().Add(-retention))
+	if err != nil {
+		log.Error("Error purging deleted stories:", err)
+		return 0, err
+	}
+
+	return purged, nil
+}
@@ -4,8 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/kodinggo/gb-2-api-comment-service/pb/comment_service"
 	"github.com/kodinggo/gb-2-api-story-service/internal/helper"
 	"github.com/kodinggo/gb-2-api-story-service/internal/model"
@@ -16,23 +16,34 @@ type StoryUsecase struct {
 	storyRepo         model.IStoryRepository
 	categoryUsecase   model.ICategoryRepository
 	grpcCommentClient comment_service.CommentServiceClient
+	accountClient     model.IAccountClient
+	tagRepo           model.ITagRepository
+	transactor        model.ITransactor
 }
 
-var v = validator.New()
+// searchSnippetLength is the number of characters of content shown around a
+// search match.
+const searchSnippetLength = 160
 
 func NewStoryUsecase(
 	storyRepo model.IStoryRepository,
 	grpcCommentClient comment_service.CommentServiceClient,
-	categoryUsecase model.ICategoryUsecase,
+	categoryUsecase model.ICategoryRepository,
+	accountClient model.IAccountClient,
+	tagRepo model.ITagRepository,
+	transactor model.ITransactor,
 ) model.IStoryUsecase {
 	return &StoryUsecase{
 		storyRepo:         storyRepo,
 		categoryUsecase:   categoryUsecase,
 		grpcCommentClient: grpcCommentClient,
+		accountClient:     accountClient,
+		tagRepo:           tagRepo,
+		transactor:        transactor,
 	}
 }
 
-func (s *StoryUsecase) FindAll(ctx context.Context, filter model.FindAllParam) ([]*model.Story, error) {
+func (s *StoryUsecase) FindAll(ctx context.Context, filter model.FindAllParam) ([]*model.Story, *model.Pagination, error) {
 	if filter.Limit <= 0 {
 		filter.Limit = model.DefaultLimit
 	}
@@ -41,22 +52,130 @@ func (s *StoryUsecase) FindAll(ctx context.Context, filter model.FindAllParam) (
 		filter.Page = model.DefaultPage
 	}
 
+	if filter.Status == "" {
+		filter.Status = model.StoryStatusPublished
+	}
+
+	if filter.IncludeComments && filter.CommentsLimit <= 0 {
+		filter.CommentsLimit = model.DefaultCommentsLimit
+	}
+
 	log := logrus.WithFields(logrus.Fields{
 		"ctx":   ctx,
 		"limit": filter.Limit,
 		"page":  filter.Page,
+		"q":     filter.Query,
+		"sort":  filter.Sort,
+		"tag":   filter.Tag,
 	})
 
+	err := validate(ctx, filter)
+	if err != nil {
+		log.Error("Validation error:", err)
+		return nil, nil, err
+	}
+
+	err = authorizeStatusFilter(ctx, filter)
+	if err != nil {
+		log.Error("Unauthorized status filter:", err)
+		return nil, nil, err
+	}
+
+	// Cursors are keyed on (created_at, id), so they only work with the date sorts
+	keyset := filter.Sort == model.SortNewest || filter.Sort == model.SortOldest || (filter.Sort == "" && filter.Query == "")
+	if filter.Cursor != nil && !keyset {
+		log.Error("Cursor used with unsupported sort")
+		return nil, nil, model.ErrInvalidCursor
+	}
+
+	if filter.IncludeDescendants && len(filter.CategoryIDs) > 0 {
+		categories, err := s.categoryUsecase.FindAll(ctx)
+		if err != nil {
+			log.Error("Error fetching categories: ", err)
+			return nil, nil, err
+		}
+		filter.CategoryIDs = descendantIDs(categories, filter.CategoryIDs)
+	}
+
+	// Fetch one extra story to find out whether another page follows
 	storyFilter := model.FindAllParam{
-		Limit: filter.Limit,
-		Page:  filter.Page,
+		Limit:       filter.Limit + 1,
+		Page:        filter.Page,
+		Offset:      (filter.Page - 1) * filter.Limit,
+		Query:       filter.Query,
+		CategoryIDs: filter.CategoryIDs,
+		UserID:      filter.UserID,
+		Status:      filter.Status,
+		CreatedFrom: filter.CreatedFrom,
+		CreatedTo:   filter.CreatedTo,
+		Sort:        filter.Sort,
+		Cursor:      filter.Cursor,
+		Tag:         filter.Tag,
 	}
 
 	story, err := s.storyRepo.FindAll(ctx, storyFilter)
 	if err != nil {
 		log.Error("Error fetching stories: ", err)
-		return nil, err
+		return nil, nil, err
+	}
+
+	pagination := &model.Pagination{}
+	backward := filter.Cursor != nil && filter.Cursor.Backward
+	hasMore := int64(len(story)) > filter.Limit
+	if hasMore {
+		if backward {
+			story = story[1:]
+		} else {
+			story = story[:filter.Limit]
+		}
 	}
+
+	pagination.HasMore = hasMore || backward
+	if keyset && len(story) > 0 {
+		first, last := story[0], story[len(story)-1]
+		if pagination.HasMore {
+			pagination.NextCursor = model.StoryCursor{CreatedAt: last.CreatedAt, Id: last.Id}.Encode()
+		}
+		if (backward && hasMore) || (!backward && filter.Cursor != nil) {
+			pagination.PrevCursor = model.StoryCursor{CreatedAt: first.CreatedAt, Id: first.Id, Backward: true}.Encode()
+		}
+	}
+
+	s.resolveAuthors(ctx, story)
+
+	err = s.attachTags(ctx, story)
+	if err != nil {
+		log.Error("Error fetching tags: ", err)
+		return nil, nil, err
+	}
+
+	if filter.WithTotal {
+		total, err := s.storyRepo.Count(ctx, storyFilter)
+		if err != nil {
+			log.Error("Error counting stories: ", err)
+			return nil, nil, err
+		}
+		pagination.Total = &total
+	}
+
+	if filter.Query != "" {
+		terms := helper.SearchTerms(filter.Query)
+		for _, result := range story {
+			result.Highlight = &model.Highlight{
+				Title:   helper.Highlight(result.Title, terms),
+				Content: helper.Snippet(result.Content, terms, searchSnippetLength),
+			}
+		}
+	}
+
+	// Listings carry the stored comment counts, kept fresh by SyncCommentCounts,
+	// and only call the comment service when asked for comments
+	if !filter.IncludeComments || len(story) == 0 {
+		return story, pagination, nil
+	}
+
+	// The comment service can only return every comment of the stories, so
+	// the latest ones are picked here
 	var storyIDs []int64
 	for _,results := range story{
 		storyIDs = append(storyIDs, results.Id)
@@ -64,9 +183,11 @@ func (s *StoryUsecase) FindAll(ctx context.Context, filter model.FindAllParam) (
 	commentPb,err := s.grpcCommentClient.FindAllByStoryIDs(ctx,&comment_service.FindAllByStoryIDsRequest{
 		StoryId: storyIDs,
 	})
-	if err !=nil{
-		log.Error("err fetching comments :",err)
-		return nil,err
+	if err != nil {
+		// Stories are still worth showing without their comments
+		log.Warn("Comments unavailable: ", err)
+		markCommentsUnavailable(story)
+		return story, pagination, nil
 	}
 
 	if commentPb != nil{
@@ -78,8 +199,14 @@ func (s *StoryUsecase) FindAll(ctx context.Context, filter model.FindAllParam) (
 	for _, storyComment := range story {
 		storyComment.Comments  = commentsByStoryID[storyComment.Id]
 	}
+	// The fetched comments give a fresher count than the stored one, which is
+	// only written by SyncCommentCounts so reads stay read-only
+	for _, storyComment := range story {
+		storyComment.CommentCount = int64(len(storyComment.Comments))
+		storyComment.Comments = latestComments(storyComment.Comments, filter.CommentsLimit)
+	}
 }
-	return story, nil
+	return story, pagination, nil
 }
 
 func (s *StoryUsecase) FindById(ctx context.Context, id int64) (*model.Story, error) {
@@ -92,28 +219,104 @@ func (s *StoryUsecase) FindById(ctx context.Context, id int64) (*model.Story, er
 		log.Error(err)
 		return nil, err
 	}
-	if story.DeletedAt.Valid {
-		return nil, fmt.Errorf("story not found")
+	err = authorizeStoryRead(ctx, story)
+	if err != nil {
+		log.Error("Unauthorized story read:", err)
+		return nil, err
 	}
-	commentPb, err := s.grpcCommentClient.FindAllByStoryID(ctx, &comment_service.FindAllByStoryIDRequest{
-		StoryId: id,
+	return s.withDetails(ctx, story)
+}
+
+// FindBySlug looks a story up by its current or an old slug. A story found by
+// an old slug is returned without details; its Slug differs from the one
+// asked for so callers can redirect.
+func (s *StoryUsecase) FindBySlug(ctx context.Context, slug string) (*model.Story, error) {
+	log := logrus.WithFields(logrus.Fields{
+		"ctx":  ctx,
+		"slug": slug,
 	})
-	
-	if err != nil  {
-		return nil,err
+
+	story, err := s.storyRepo.FindBySlug(ctx, slug)
+	if errors.Is(err, model.ErrNotFound) {
+		id, redirectErr := s.storyRepo.FindSlugRedirect(ctx, slug)
+		if redirectErr != nil {
+			log.Error(redirectErr)
+			return nil, redirectErr
+		}
+		story, err = s.storyRepo.FindById(ctx, id)
+	}
+	if err != nil {
+		log.Error(err)
+		return nil, err
 	}
-	if commentPb != nil {
-		commentPb := helper.ConvertPbCommentToModelComments(commentPb.Comments)
-		var commentList []*model.Comment
-        for _, comment := range commentPb {
-            commentList = append(commentList, comment) // Dereferensi pointer
-        }
-		story.Comments = commentList
+
+	err = authorizeStoryRead(ctx, story)
+	if err != nil {
+		log.Error("Unauthorized story read:", err)
+		return nil, err
+	}
+
+	if story.Slug != slug {
+		return story, nil
+	}
+
+	return s.withDetails(ctx, story)
+}
+
+// withDetails adds the author and tags shown on a single story. Comments are
+// paged through FindComments instead, with the stored comment count shown
+// here.
+func (s *StoryUsecase) withDetails(ctx context.Context, story *model.Story) (*model.Story, error) {
+	s.resolveAuthors(ctx, []*model.Story{story})
+	if err := s.attachTags(ctx, []*model.Story{story}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx": ctx,
+			"id":  story.Id,
+		}).Error("Error fetching tags: ", err)
+		return nil, err
 	}
 	return story, nil
 }
 
-func (s *StoryUsecase) Create(ctx context.Context, in model.CreateStoryInput) error {
+func (s *StoryUsecase) FindByIDs(ctx context.Context, ids []int64) ([]*model.Story, error) {
+	log := logrus.WithFields(logrus.Fields{
+		"ctx": ctx,
+		"ids": ids,
+	})
+
+	ids = uniqueIDs(ids)
+	if len(ids) > model.MaxFindByIDs {
+		err := model.NewValidationError("ids", fmt.Sprintf("must have at most %d ids", model.MaxFindByIDs))
+		log.Error("Validation error:", err)
+		return nil, err
+	}
+
+	stories, err := s.storyRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		log.Error("Error fetching stories: ", err)
+		return nil, err
+	}
+
+	// Stories the caller may not read are left out, as if they did not exist
+	visible := stories[:0]
+	for _, story := range stories {
+		if authorizeStoryRead(ctx, story) == nil {
+			visible = append(visible, story)
+		}
+	}
+	stories = visible
+	s.resolveAuthors(ctx, stories)
+
+	err = s.attachTags(ctx, stories)
+	if err != nil {
+		log.Error("Error fetching tags: ", err)
+		return nil, err
+	}
+
+	return stories, nil
+}
+
+func (s *StoryUsecase) Create(ctx context.Context, in model.CreateStoryInput) (*model.Story, error) {
 	log := logrus.WithFields(logrus.Fields{
 		"ctx":           ctx,
 		"title":         in.Title,
@@ -122,42 +325,82 @@ func (s *StoryUsecase) Create(ctx context.Context, in model.CreateStoryInput) er
 		"category_id":   in.CategoryId,
 	})
 
-	err := v.StructCtx(ctx, in)
+	userID, ok := model.UserIDFromContext(ctx)
+	if !ok {
+		log.Error("Missing author")
+		return nil, model.ErrUnauthenticated
+	}
+
+	err := validate(ctx, in)
 	if err != nil {
 		log.Error("Validation error:", err)
-		return err
+		return nil, err
 	}
 
-	category, err := s.categoryUsecase.FindById(ctx, int64(in.CategoryId))
+	tags, err := normalizeTags(in.Tags)
 	if err != nil {
-		log.Error("Error fetching category:", err)
-		return err
+		log.Error("Validation error:", err)
+		return nil, err
 	}
 
-	if category == nil {
-		log.Error("Category not found", err)
-		return errors.New("category not found")
+	// New stories start as drafts unless published or scheduled right away
+	if in.Status == "" {
+		in.Status = model.StoryStatusDraft
+	}
+
+	publishedAt, err := publishedAtFor(in.Status, in.PublishAt, nil, time.Now())
+	if err != nil {
+		log.Error("Validation error:", err)
+		return nil, err
 	}
 
 	story := model.Story{
+		Status:       in.Status,
+		PublishedAt:  publishedAt,
 		Title:        in.Title,
 		Content:      in.Content,
 		ThumbnailUrl: in.ThumbnailUrl,
 		Category: model.Category{
 			Id: int64(in.CategoryId),
 		},
+		Author: model.Account{
+			Id: userID,
+		},
 	}
 
-	err = s.storyRepo.Create(ctx, story)
+	var created *model.Story
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		err := s.checkCategoryExists(ctx, story.Category.Id)
+		if err != nil {
+			return err
+		}
+
+		created, err = s.storyRepo.Create(ctx, story)
+		if err != nil {
+			return err
+		}
+
+		return s.saveTags(ctx, created.Id, tags)
+	})
 	if err != nil {
 		log.Error("Error creating story:", err)
-		return err
+		return nil, err
 	}
 
-	return nil
+	s.refreshCategoryStats(ctx, created.Category.Id)
+
+	s.resolveAuthors(ctx, []*model.Story{created})
+
+	err = s.attachTags(ctx, []*model.Story{created})
+	if err != nil {
+		log.Error("Error fetching tags:", err)
+		return nil, err
+	}
+
+	return created, nil
 }
 
-func (s *StoryUsecase) Update(ctx context.Context, id int64, in model.UpdateStoryInput) error {
+func (s *StoryUsecase) Update(ctx context.Context, id int64, version int64, in model.UpdateStoryInput) (*model.Story, error) {
 	log := logrus.WithFields(logrus.Fields{
 		"ctx":           ctx,
 		"id":            id,
@@ -167,25 +410,39 @@ func (s *StoryUsecase) Update(ctx context.Context, id int64, in model.UpdateStor
 		"category_id":   in.CategoryId,
 	})
 
-	err := v.StructCtx(ctx, in)
+	err := validate(ctx, in)
 	if err != nil {
 		log.Error("Validation error:", err)
-		return err
+		return nil, err
 	}
 
-	category, err := s.categoryUsecase.FindById(ctx, int64(in.CategoryId))
+	tags, err := normalizeTags(in.Tags)
 	if err != nil {
-		log.Error("Error fetching category:", err)
-		return err
+		log.Error("Validation error:", err)
+		return nil, err
+	}
+
+	story, err := s.storyRepo.FindById(ctx, id)
+	if err != nil {
+		log.Error("Error fetching story:", err)
+		return nil, err
+	}
+
+	err = authorizeStoryMutation(ctx, story)
+	if err != nil {
+		log.Error("Unauthorized story update:", err)
+		return nil, err
 	}
 
-	if category == nil {
-		log.Error("Category not found")
-		return errors.New("category not found")
+	err = checkVersion(story, version)
+	if err != nil {
+		log.Error("Stale story update:", err)
+		return nil, err
 	}
 
 	updatedStory := model.Story{
 		Id:           id,
+		Version:      version,
 		Title:        in.Title,
 		Content:      in.Content,
 		ThumbnailUrl: in.ThumbnailUrl,
@@ -194,22 +451,360 @@ func (s *StoryUsecase) Update(ctx context.Context, id int64, in model.UpdateStor
 		},
 	}
 
-	err = s.storyRepo.Update(ctx, updatedStory)
+	var updated *model.Story
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		err := s.checkCategoryExists(ctx, updatedStory.Category.Id)
+		if err != nil {
+			return err
+		}
+
+		updated, err = s.storyRepo.Update(ctx, updatedStory)
+		if err != nil {
+			return err
+		}
+
+		// Omitted tags are left as they are
+		if in.Tags == nil {
+			return nil
+		}
+
+		return s.saveTags(ctx, id, tags)
+	})
 	if err != nil {
 		log.Error("Error updating story:", err)
+		return nil, err
+	}
+
+	s.refreshCategoryStats(ctx, story.Category.Id, updated.Category.Id)
+
+	s.resolveAuthors(ctx, []*model.Story{updated})
+
+	err = s.attachTags(ctx, []*model.Story{updated})
+	if err != nil {
+		log.Error("Error fetching tags:", err)
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (s *StoryUsecase) Patch(ctx context.Context, id int64, version int64, in model.PatchStoryInput) (*model.Story, error) {
+	log := logrus.WithFields(logrus.Fields{
+		"ctx": ctx,
+		"id":  id,
+	})
+
+	err := validate(ctx, in)
+	if err != nil {
+		log.Error("Validation error:", err)
+		return nil, err
+	}
+
+	tags, err := normalizeTags(in.Tags)
+	if err != nil {
+		log.Error("Validation error:", err)
+		return nil, err
+	}
+
+	story, err := s.storyRepo.FindById(ctx, id)
+	if err != nil {
+		log.Error("Error fetching story:", err)
+		return nil, err
+	}
+
+	err = authorizeStoryMutation(ctx, story)
+	if err != nil {
+		log.Error("Unauthorized story update:", err)
+		return nil, err
+	}
+
+	err = checkVersion(story, version)
+	if err != nil {
+		log.Error("Stale story update:", err)
+		return nil, err
+	}
+
+	// Drop fields that already hold the requested value
+	if in.Title != nil && *in.Title == story.Title {
+		in.Title = nil
+	}
+	if in.Content != nil && *in.Content == story.Content {
+		in.Content = nil
+	}
+	if in.ThumbnailUrl != nil && *in.ThumbnailUrl == story.ThumbnailUrl {
+		in.ThumbnailUrl = nil
+	}
+	if in.CategoryId != nil && int64(*in.CategoryId) == story.Category.Id {
+		in.CategoryId = nil
+	}
+
+	err = s.attachTags(ctx, []*model.Story{story})
+	if err != nil {
+		log.Error("Error fetching tags:", err)
+		return nil, err
+	}
+	if in.Tags != nil && !tagsChanged(story.Tags, tags) {
+		in.Tags = nil
+	}
+
+	if in.IsEmpty() && in.Tags == nil {
+		s.resolveAuthors(ctx, []*model.Story{story})
+		return story, nil
+	}
+
+	// Tags live outside the story row, so changing only them still bumps the
+	// version for If-Match to cover them
+	var patched *model.Story
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if in.CategoryId != nil {
+			err := s.checkCategoryExists(ctx, int64(*in.CategoryId))
+			if err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if in.IsEmpty() {
+			patched, err = s.storyRepo.Touch(ctx, id, version)
+		} else {
+			patched, err = s.storyRepo.Patch(ctx, id, version, in)
+		}
+		if err != nil {
+			return err
+		}
+
+		if in.Tags == nil {
+			return nil
+		}
+
+		return s.saveTags(ctx, id, tags)
+	})
+	if err != nil {
+		log.Error("Error patching story:", err)
+		return nil, err
+	}
+
+	if in.CategoryId != nil {
+		s.refreshCategoryStats(ctx, story.Category.Id, patched.Category.Id)
+	}
+
+	s.resolveAuthors(ctx, []*model.Story{patched})
+
+	err = s.attachTags(ctx, []*model.Story{patched})
+	if err != nil {
+		log.Error("Error fetching tags:", err)
+		return nil, err
+	}
+
+	return patched, nil
+}
+
+func (s *StoryUsecase) Delete(ctx context.Context, id int64, version int64) error {
+	log := logrus.WithFields(logrus.Fields{
+		"ctx": ctx,
+		"id":  id,
+	})
+
+	story, err := s.storyRepo.FindById(ctx, id)
+	if err != nil {
+		log.Error("Error fetching story:", err)
 		return err
 	}
 
+	err = authorizeStoryMutation(ctx, story)
+	if err != nil {
+		log.Error("Unauthorized story delete:", err)
+		return err
+	}
+
+	err = checkVersion(story, version)
+	if err != nil {
+		log.Error("Stale story delete:", err)
+		return err
+	}
+
+	err = s.storyRepo.Delete(ctx, id, version)
+	if err != nil {
+		log.Error("Failed to delete story:", err)
+		return err
+	}
+
+	s.refreshCategoryStats(ctx, story.Category.Id)
+
 	return nil
 }
 
-func (s *StoryUsecase) Delete(ctx context.Context, id int64) error {
-	err := s.storyRepo.Delete(ctx, id)
+func (s *StoryUsecase) FindRevisions(ctx context.Context, storyId int64) ([]*model.StoryRevision, error) {
+	log := logrus.WithFields(logrus.Fields{
+		"ctx":      ctx,
+		"story_id": storyId,
+	})
+
+	story, err := s.storyRepo.FindById(ctx, storyId)
+	if err != nil {
+		log.Error("Error fetching story:", err)
+		return nil, err
+	}
+
+	// Revisions hold text editors may have removed, so only those allowed to
+	// edit the story can read them
+	err = authorizeStoryMutation(ctx, story)
+	if err != nil {
+		log.Error("Unauthorized revision read:", err)
+		return nil, err
+	}
+
+	revisions, err := s.storyRepo.FindRevisions(ctx, storyId)
+	if err != nil {
+		log.Error("Error fetching revisions:", err)
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// FindRevision returns the revision along with the diff from it to the
+// current story.
+func (s *StoryUsecase) FindRevision(ctx context.Context, storyId int64, revision int64) (*model.StoryRevision, error) {
+	log := logrus.WithFields(logrus.Fields{
+		"ctx":      ctx,
+		"story_id": storyId,
+		"revision": revision,
+	})
+
+	story, err := s.storyRepo.FindById(ctx, storyId)
+	if err != nil {
+		log.Error("Error fetching story:", err)
+		return nil, err
+	}
+
+	err = authorizeStoryMutation(ctx, story)
+	if err != nil {
+		log.Error("Unauthorized revision read:", err)
+		return nil, err
+	}
+
+	storyRevision, err := s.storyRepo.FindRevision(ctx, storyId, revision)
+	if err != nil {
+		log.Error("Error fetching revision:", err)
+		return nil, err
+	}
+
+	title, titleOk := helper.DiffLines(storyRevision.Title, story.Title)
+	content, contentOk := helper.DiffLines(storyRevision.Content, story.Content)
+	if titleOk && contentOk {
+		storyRevision.Diff = &model.StoryDiff{Title: title, Content: content}
+	} else {
+		storyRevision.Diff = &model.StoryDiff{Unavailable: true}
+	}
+
+	return storyRevision, nil
+}
+
+// RestoreRevision writes the revision back as a regular update, so the state
+// it replaces is kept as a revision as well.
+func (s *StoryUsecase) RestoreRevision(ctx context.Context, storyId int64, revision int64, version int64) (*model.Story, error) {
+	log := logrus.WithFields(logrus.Fields{
+		"ctx":      ctx,
+		"story_id": storyId,
+		"revision": revision,
+	})
+
+	storyRevision, err := s.storyRepo.FindRevision(ctx, storyId, revision)
 	if err != nil {
+		log.Error("Error fetching revision:", err)
+		return nil, err
+	}
+
+	return s.Update(ctx, storyId, version, model.UpdateStoryInput{
+		Title:        storyRevision.Title,
+		Content:      storyRevision.Content,
+		ThumbnailUrl: storyRevision.ThumbnailUrl,
+		CategoryId:   int(storyRevision.CategoryId),
+	})
+}
+
+// refreshCategoryStats updates the cached story stats of the categories whose
+// stories changed. Failing leaves the stats stale until the next change, so
+// the error is only logged.
+func (s *StoryUsecase) refreshCategoryStats(ctx context.Context, categoryIds ...int64) {
+	if err := s.categoryUsecase.RefreshStats(ctx, categoryIds...); err != nil {
 		logrus.WithFields(logrus.Fields{
-			"ctx": ctx,
-			"id":  id,
-		}).Error("Failed to delete story:", err)
+			"ctx":          ctx,
+			"category_ids": categoryIds,
+		}).Error("Error refreshing category stats: ", err)
+	}
+}
+
+// markCommentsUnavailable flags stories whose comments could not be fetched.
+// Their comment counts are left as last stored, so they may be stale.
+func markCommentsUnavailable(stories []*model.Story) {
+	for _, story := range stories {
+		story.Comments = nil
+		story.CommentsUnavailable = true
+	}
+}
+
+// resolveAuthors fills in author details from the account service. Stories
+// keep only the author ID when their author could not be looked up.
+func (s *StoryUsecase) resolveAuthors(ctx context.Context, stories []*model.Story) {
+	var userIDs []int64
+	for _, story := range stories {
+		if story.Author.Id > 0 {
+			userIDs = append(userIDs, story.Author.Id)
+		}
+	}
+
+	if len(userIDs) == 0 {
+		return
+	}
+
+	accounts, err := s.accountClient.FindByIDs(ctx, userIDs)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":      ctx,
+			"user_ids": userIDs,
+		}).Error("Error fetching authors: ", err)
+	}
+
+	for _, story := range stories {
+		if account, ok := accounts[story.Author.Id]; ok {
+			story.Author = *account
+		}
+	}
+}
+
+// checkCategoryExists rejects stories that reference a missing category. Run
+// in the transaction writing the story, it keeps the category from being
+// deleted until the story is written.
+func (s *StoryUsecase) checkCategoryExists(ctx context.Context, categoryID int64) error {
+	_, err := s.categoryUsecase.FindByIdForShare(ctx, categoryID)
+	if errors.Is(err, model.ErrNotFound) {
+		return model.NewUnprocessableError("category_id", "refers to a category that does not exist")
+	}
+
+	return err
+}
+
+// uniqueIDs drops repeated IDs, keeping the first occurrence of each.
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	unique := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	return unique
+}
+
+// checkVersion rejects writes based on a stale read. A zero version skips the
+// check.
+func checkVersion(story *model.Story, version int64) error {
+	if version > 0 && story.Version != version {
+		return model.ErrPreconditionFailed
 	}
 
 	return nil
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+)
+
+// stubCategoryRepo serves categories from a map. Methods not overridden panic
+// through the nil embedded interface.
+type stubCategoryRepo struct {
+	model.ICategoryRepository
+	categories map[int64]*model.Categories
+}
+
+func (r *stubCategoryRepo) FindById(_ context.Context, id int64) (*model.Categories, error) {
+	category, ok := r.categories[id]
+	if !ok {
+		return nil, model.NewNotFoundError("category")
+	}
+	return category, nil
+}
+
+func (r *stubCategoryRepo) FindByIdForShare(ctx context.Context, id int64) (*model.Categories, error) {
+	return r.FindById(ctx, id)
+}
+
+func (r *stubCategoryRepo) FindAncestorIDs(_ context.Context, id int64) ([]int64, error) {
+	var ids []int64
+	for ancestor := id; ; {
+		category, ok := r.categories[ancestor]
+		if !ok {
+			if ancestor == id {
+				return nil, model.NewNotFoundError("category")
+			}
+			return ids, nil
+		}
+
+		ids = append(ids, ancestor)
+		if category.ParentId == nil {
+			return ids, nil
+		}
+		ancestor = *category.ParentId
+	}
+}
+
+func TestCheckCategoryExists(t *testing.T) {
+	usecase := &StoryUsecase{
+		categoryUsecase: &stubCategoryRepo{
+			categories: map[int64]*model.Categories{1: {Id: 1, Name: "News"}},
+		},
+	}
+
+	if err := usecase.checkCategoryExists(context.Background(), 1); err != nil {
+		t.Fatalf("expected an existing category to pass, got %v", err)
+	}
+
+	err := usecase.checkCategoryExists(context.Background(), 2)
+	if !errors.Is(err, model.ErrUnprocessable) {
+		t.Fatalf("expected ErrUnprocessable, got %v", err)
+	}
+	if errors.Is(err, model.ErrNotFound) {
+		t.Fatal("expected a missing category not to be reported as not found")
+	}
+
+	var validationErr *model.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Fields["category_id"] == "" {
+		t.Fatalf("expected a category_id validation error, got %v", err)
+	}
+}
+
+func TestFindByIDs(t *testing.T) {
+	comments, stories := commentTestData()
+	usecase := newCommentTestUsecase(t, comments, stories)
+	failingComments(comments)
+
+	res, err := usecase.FindByIDs(context.Background(), []int64{2, 1, 2, 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 || res[0].Id != 2 || res[1].Id != 1 {
+		t.Fatalf("expected stories 2 and 1 once each, got %+v", res)
+	}
+
+	tooMany := make([]int64, model.MaxFindByIDs+1)
+	for i := range tooMany {
+		tooMany[i] = int64(i + 1)
+	}
+	_, err = usecase.FindByIDs(context.Background(), tooMany)
+	var validationErr *model.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Fields["ids"] == "" {
+		t.Fatalf("expected an ids validation error, got %v", err)
+	}
+
+	// Repeated IDs count once towards the limit
+	repeated := make([]int64, model.MaxFindByIDs+1)
+	for i := range repeated {
+		repeated[i] = 1
+	}
+	if _, err := usecase.FindByIDs(context.Background(), repeated); err != nil {
+		t.Fatalf("expected repeated IDs to be accepted, got %v", err)
+	}
+}
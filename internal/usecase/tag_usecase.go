@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type TagUsecase struct {
+	TagRepo model.ITagRepository
+}
+
+func NewTagUsecase(tagRepo model.ITagRepository) model.ITagUsecase {
+	return &TagUsecase{
+		TagRepo: tagRepo,
+	}
+}
+
+// FindAll lists the tags in use along with their published story counts.
+func (t *TagUsecase) FindAll(ctx context.Context) ([]*model.Tag, error) {
+	tags, err := t.TagRepo.FindAll(ctx)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx": ctx,
+		}).Error("Error fetching tags: ", err)
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (t *TagUsecase) FindBySlug(ctx context.Context, slug string) (*model.Tag, error) {
+	tag, err := t.TagRepo.FindBySlug(ctx, slug)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"ctx":  ctx,
+			"slug": slug,
+		}).Error("Error fetching tag: ", err)
+		return nil, err
+	}
+
+	return tag, nil
+}
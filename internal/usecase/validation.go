@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/kodinggo/gb-2-api-story-service/internal/model"
+)
+
+var v = newValidator()
+
+func newValidator() *validator.Validate {
+	validate := validator.New()
+
+	// Report fields by their JSON name so clients can match them to their input
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
+
+	return validate
+}
+
+// validate checks s against its validate tags and returns a
+// model.ValidationError describing every invalid field.
+func validate(ctx context.Context, s any) error {
+	err := v.StructCtx(ctx, s)
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	fields := make(map[string]string, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		// Drop the struct name, keeping paths such as positions[0].id
+		_, field, _ := strings.Cut(fieldErr.Namespace(), ".")
+		fields[field] = validationMessage(fieldErr)
+	}
+
+	return &model.ValidationError{Fields: fields}
+}
+
+func validationMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		if fieldErr.Kind() == reflect.String {
+			return fmt.Sprintf("must be at least %s characters", fieldErr.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fieldErr.Param())
+	case "max":
+		if fieldErr.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters", fieldErr.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fieldErr.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.ReplaceAll(fieldErr.Param(), " ", ", "))
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", fieldErr.Param())
+	case "gte":
+		return fmt.Sprintf("must be at least %s", fieldErr.Param())
+	case "url":
+		return "must be a valid URL"
+	case "hexcolor":
+		return "must be a hex color such as #1a2b3c"
+	case "unique":
+		return "must not contain duplicates"
+	default:
+		return "is invalid"
+	}
+}